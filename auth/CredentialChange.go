@@ -0,0 +1,51 @@
+package auth
+
+import (
+	crun "github.com/pip-services3-gox/pip-services3-commons-gox/run"
+)
+
+// CredentialChangeArgKey is a key of notification arguments that holds *CredentialChange
+const CredentialChangeArgKey = "change"
+
+// CredentialChange describes a change of credential parameters in a credential store.
+// When credentials are rotated the active credential is replaced with a new version
+// and the replaced one is kept as previous, so clients can reconnect using the new credential
+// while servers still accept the previous one.
+//	see MemoryCredentialStore
+//	see CredentialChangeFromArgs
+type CredentialChange struct {
+	// The key of changed credential parameters
+	Key string
+
+	// The version of the change. Versions of a key grow with every rotation and removal
+	Version int
+
+	// The active credential or nil when credential was removed
+	Credential *CredentialParams
+
+	// The previous credential or nil when there was no previous version
+	Previous *CredentialParams
+}
+
+// IsRemoved checks if credential parameters were removed from the store.
+//	Returns: bool true if credential was removed.
+func (c *CredentialChange) IsRemoved() bool {
+	return c.Credential == nil
+}
+
+// CredentialChangeFromArgs gets a credential change from notification arguments.
+//	see CredentialChangeArgKey
+//	Parameters:
+//		- args *crun.Parameters notification arguments.
+//	Returns: *CredentialChange, bool the change and true if arguments contain it.
+func CredentialChangeFromArgs(args *crun.Parameters) (*CredentialChange, bool) {
+	if args == nil {
+		return nil, false
+	}
+	value, ok := args.Get(CredentialChangeArgKey)
+	if !ok {
+		return nil, false
+	}
+	change, ok := value.(*CredentialChange)
+	return change, ok && change != nil
+}
@@ -20,6 +20,8 @@ import (
 //		- access_key: application secret key
 //		- client_key: alternative to access_key
 //		- secret_key: alternative to access_key
+//		- ssl_cert_file: path to PEM file with client TLS certificate
+//		- ssl_key_file: path to PEM file with client TLS private key
 //
 // In addition to standard parameters CredentialParams may contain any number of custom parameters
 // see config.ConfigParams
@@ -52,6 +54,8 @@ const (
 	CredentialParamAccessKey   string = "access_key"
 	CredentialParamClientKey   string = "client_key"
 	CredentialParamSecretKey   string = "secret_key"
+	CredentialParamSslCertFile string = "ssl_cert_file"
+	CredentialParamSslKeyFile  string = "ssl_key_file"
 )
 
 // NewEmptyCredentialParams creates a new credential parameters and fills it with values.
@@ -252,3 +256,27 @@ func (c *CredentialParams) AccessKey() string {
 func (c *CredentialParams) SetAccessKey(value string) {
 	c.Put(CredentialParamAccessKey, value)
 }
+
+// SslCertFile gets the path to PEM file with client TLS certificate.
+//	Returns: string the path to client certificate file.
+func (c *CredentialParams) SslCertFile() string {
+	return c.GetAsString(CredentialParamSslCertFile)
+}
+
+// SetSslCertFile sets the path to PEM file with client TLS certificate.
+//	Parameters: value string a new path to client certificate file.
+func (c *CredentialParams) SetSslCertFile(value string) {
+	c.Put(CredentialParamSslCertFile, value)
+}
+
+// SslKeyFile gets the path to PEM file with client TLS private key.
+//	Returns: string the path to client key file.
+func (c *CredentialParams) SslKeyFile() string {
+	return c.GetAsString(CredentialParamSslKeyFile)
+}
+
+// SetSslKeyFile sets the path to PEM file with client TLS private key.
+//	Parameters: value string a new path to client key file.
+func (c *CredentialParams) SetSslKeyFile(value string) {
+	c.Put(CredentialParamSslKeyFile, value)
+}
@@ -124,6 +124,9 @@ func (c *CredentialResolver) lookupInStores(ctx context.Context, correlationId s
 
 	for _, component := range components {
 		if store, ok := component.(ICredentialStore); ok && store != nil {
+			if err = ctx.Err(); err != nil {
+				return nil, err
+			}
 			credential, err = store.Lookup(ctx, correlationId, key)
 			if credential != nil || err != nil {
 				return credential, err
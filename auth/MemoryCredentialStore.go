@@ -2,12 +2,24 @@ package auth
 
 import (
 	"context"
+	refl "reflect"
+	"sync"
 
 	"github.com/pip-services3-gox/pip-services3-commons-gox/config"
 	"github.com/pip-services3-gox/pip-services3-commons-gox/errors"
+	crun "github.com/pip-services3-gox/pip-services3-commons-gox/run"
 )
 
 // MemoryCredentialStore Credential store that keeps credentials in memory.
+// The store is safe for concurrent use. Every stored credential gets a new version,
+// and the replaced credential is kept as previous until a next rotation or until
+// ExpirePrevious is called, so servers can accept both credentials while clients reconnect.
+// Versions of a key keep growing when it is removed and stored again.
+// Change listeners are notified with *CredentialChange under CredentialChangeArgKey
+// every time a credential is rotated or removed. Notifications are delivered one at a time
+// in the order of versions. Listeners may use the store, and changes they make are delivered
+// after the current notification, so Store may return before its change is delivered.
+// The store keeps its own copies of credentials and returns copies to callers.
 //		Configuration parameters:
 //			- [credential key 1]:
 //				- ... credential parameters for key 1
@@ -16,6 +28,7 @@ import (
 //				- ...
 // see ICredentialStore
 // see CredentialParams
+// see CredentialChange
 //
 //	Example:
 //		config := NewConfigParamsFromTuples(
@@ -27,15 +40,43 @@ import (
 //		credentialStore := NewEmptyMemoryCredentialStore();
 //		credentialStore.ReadCredentials(config);
 //		res, err := credentialStore.Lookup(context.Backgroudn(), "123", "key1");
+//
+//		credentialStore.AddChangeListener(context.Background(), listener);
+//		err = credentialStore.Store(context.Background(), "123", "key1", NewCredentialParamsFromTuples(
+//			"user", "jdoe",
+//			"pass", "newpass456",
+//		));
+//		version := credentialStore.Version("key1");                                         // Result: 2
+//		previous, err := credentialStore.LookupPrevious(context.Background(), "123", "key1"); // Result: pass123
 type MemoryCredentialStore struct {
-	items map[string]*CredentialParams
+	items       map[string]*memoryCredentialEntry
+	versions    map[string]int
+	listeners   []crun.INotifiable
+	pending     []*memoryCredentialNotification
+	dispatching bool
+	mtx         sync.RWMutex
+}
+
+// memoryCredentialNotification is a change waiting to be delivered to listeners.
+type memoryCredentialNotification struct {
+	ctx           context.Context
+	correlationId string
+	change        *CredentialChange
+}
+
+// memoryCredentialEntry keeps active and previous versions of a credential.
+type memoryCredentialEntry struct {
+	active   *CredentialParams
+	previous *CredentialParams
 }
 
 // NewEmptyMemoryCredentialStore creates a new instance of the credential store.
 //	Returns: *MemoryCredentialStore
 func NewEmptyMemoryCredentialStore() *MemoryCredentialStore {
 	return &MemoryCredentialStore{
-		items: make(map[string]*CredentialParams),
+		items:     make(map[string]*memoryCredentialEntry),
+		versions:  make(map[string]int),
+		listeners: make([]crun.INotifiable, 0),
 	}
 }
 
@@ -45,9 +86,7 @@ func NewEmptyMemoryCredentialStore() *MemoryCredentialStore {
 //		- config *config.ConfigParams configuration with credential parameters.
 //	Returns: *MemoryCredentialStore
 func NewMemoryCredentialStore(ctx context.Context, config *config.ConfigParams) *MemoryCredentialStore {
-	c := &MemoryCredentialStore{
-		items: make(map[string]*CredentialParams),
-	}
+	c := NewEmptyMemoryCredentialStore()
 
 	if config != nil {
 		c.Configure(ctx, config)
@@ -60,40 +99,103 @@ func NewMemoryCredentialStore(ctx context.Context, config *config.ConfigParams)
 //	Parameters:
 //		- config *config.ConfigParams configuration parameters to be set.
 func (c *MemoryCredentialStore) Configure(ctx context.Context, config *config.ConfigParams) {
-	c.ReadCredentials(config)
+	c.readCredentials(ctx, config)
 }
 
 // ReadCredentials reads credentials from configuration parameters.
-// Each section represents an individual CredentialParams
+// Each section represents an individual CredentialParams.
+// Changed credentials are rotated, and credentials missing in the configuration are removed.
 //	Parameters:
 //		- config *config.ConfigParams configuration parameters to be read
 func (c *MemoryCredentialStore) ReadCredentials(config *config.ConfigParams) {
-	c.items = make(map[string]*CredentialParams)
+	c.readCredentials(context.Background(), config)
+}
 
-	sections := config.GetSectionNames()
-	for _, section := range sections {
+func (c *MemoryCredentialStore) readCredentials(ctx context.Context, config *config.ConfigParams) {
+	credentials := make(map[string]*CredentialParams)
+	for _, section := range config.GetSectionNames() {
 		value := config.GetSection(section)
-		credential := NewCredentialParams(value.Value())
-		c.items[section] = credential
+		credentials[section] = NewCredentialParams(value.Value())
+	}
+
+	c.mtx.Lock()
+	for key := range c.items {
+		if _, ok := credentials[key]; !ok {
+			c.enqueueChange(ctx, "", c.put(key, nil))
+		}
+	}
+	for key, credential := range credentials {
+		c.enqueueChange(ctx, "", c.put(key, credential))
+	}
+	c.mtx.Unlock()
+
+	c.notifyChangeListeners()
+}
+
+// put stores or removes a credential. It must be called under the lock.
+// Versions are kept after removal, so they never go backwards for the key.
+// Returns a change or nil if nothing was changed.
+func (c *MemoryCredentialStore) put(key string, credential *CredentialParams) *CredentialChange {
+	entry, ok := c.items[key]
+
+	if credential == nil {
+		if !ok {
+			return nil
+		}
+		delete(c.items, key)
+		c.versions[key]++
+		return &CredentialChange{
+			Key:      key,
+			Version:  c.versions[key],
+			Previous: copyCredential(entry.active),
+		}
+	}
+
+	if !ok {
+		entry = &memoryCredentialEntry{}
+		c.items[key] = entry
+	} else if entry.active != nil && refl.DeepEqual(entry.active.Value(), credential.Value()) {
+		// Storing the same credential is not a rotation
+		return nil
+	}
+
+	entry.previous = entry.active
+	entry.active = copyCredential(credential)
+	c.versions[key]++
+
+	return &CredentialChange{
+		Key:        key,
+		Version:    c.versions[key],
+		Credential: copyCredential(entry.active),
+		Previous:   copyCredential(entry.previous),
+	}
+}
+
+// copyCredential copies credential parameters, so changes of the copy don't affect the store.
+func copyCredential(credential *CredentialParams) *CredentialParams {
+	if credential == nil {
+		return nil
 	}
+	return NewCredentialParams(credential.Value())
 }
 
 // Store credential parameters into the store.
+// When the key already has a different credential, it is rotated: the new credential
+// becomes active with the next version and the old one is kept as previous.
 //	Parameters:
 //		- ctx context.Context.
 //		- correlationId string transaction id to trace execution through call chain.
 //		- key string a key to uniquely identify the credential parameters.
-//		- credential *CredentialParams a credential parameters to be stored.
+//		- credential *CredentialParams a credential parameters to be stored or nil to remove them.
 //	Returns: error
 func (c *MemoryCredentialStore) Store(ctx context.Context, correlationId string, key string,
 	credential *CredentialParams) error {
 
-	if credential != nil {
-		c.items[key] = credential
-	} else {
-		delete(c.items, key)
-	}
+	c.mtx.Lock()
+	c.enqueueChange(ctx, correlationId, c.put(key, credential))
+	c.mtx.Unlock()
 
+	c.notifyChangeListeners()
 	return nil
 }
 
@@ -106,10 +208,134 @@ func (c *MemoryCredentialStore) Store(ctx context.Context, correlationId string,
 func (c *MemoryCredentialStore) Lookup(ctx context.Context, correlationId string,
 	key string) (result *CredentialParams, err error) {
 
-	if credential, ok := c.items[key]; ok && credential != nil {
-		return credential, nil
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	if entry, ok := c.items[key]; ok && entry.active != nil {
+		return copyCredential(entry.active), nil
 	}
 
 	return nil, errors.NewConfigError(
 		correlationId, "MISSING_CREDENTIALS", "missing credential param: "+key)
 }
+
+// LookupPrevious gets the previous version of credential parameters replaced by the last rotation.
+//	Parameters:
+//		- ctx context.Context.
+//		- correlationId string transaction id to trace execution through call chain.
+//		- key string a key to uniquely identify the credential parameters.
+//	Returns: result *CredentialParams, err error the previous credential or nil if there is none,
+//	or error if the key is missing.
+func (c *MemoryCredentialStore) LookupPrevious(ctx context.Context, correlationId string,
+	key string) (result *CredentialParams, err error) {
+
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	if entry, ok := c.items[key]; ok {
+		return copyCredential(entry.previous), nil
+	}
+
+	return nil, errors.NewConfigError(
+		correlationId, "MISSING_CREDENTIALS", "missing credential param: "+key)
+}
+
+// Version gets the version of the last change of credential parameters.
+//	Parameters:
+//		- key string a key to uniquely identify the credential parameters.
+//	Returns: int the version starting from 1 or 0 if the key was never stored.
+func (c *MemoryCredentialStore) Version(key string) int {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	return c.versions[key]
+}
+
+// ExpirePrevious removes the previous version of credential parameters
+// when the rotation is completed and it shall not be accepted anymore.
+//	Parameters:
+//		- ctx context.Context.
+//		- correlationId string transaction id to trace execution through call chain.
+//		- key string a key to uniquely identify the credential parameters.
+func (c *MemoryCredentialStore) ExpirePrevious(ctx context.Context, correlationId string, key string) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if entry, ok := c.items[key]; ok {
+		entry.previous = nil
+	}
+}
+
+// AddChangeListener adds a listener that will be notified when credentials are rotated or removed.
+//	Parameters:
+//		- ctx context.Context
+//		- listener crun.INotifiable a listener to be added.
+func (c *MemoryCredentialStore) AddChangeListener(ctx context.Context, listener crun.INotifiable) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.listeners = append(c.listeners, listener)
+}
+
+// RemoveChangeListener removes a previously added change listener.
+//	Parameters:
+//		- ctx context.Context
+//		- listener crun.INotifiable a listener to be removed.
+func (c *MemoryCredentialStore) RemoveChangeListener(ctx context.Context, listener crun.INotifiable) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	for index, item := range c.listeners {
+		if item == listener {
+			c.listeners = append(c.listeners[:index], c.listeners[index+1:]...)
+			break
+		}
+	}
+}
+
+// enqueueChange queues the change to notify listeners. It must be called under the lock,
+// so changes are queued in the order of their versions.
+func (c *MemoryCredentialStore) enqueueChange(ctx context.Context, correlationId string, change *CredentialChange) {
+	if change != nil {
+		c.pending = append(c.pending, &memoryCredentialNotification{
+			ctx:           ctx,
+			correlationId: correlationId,
+			change:        change,
+		})
+	}
+}
+
+// notifyChangeListeners delivers queued changes to listeners outside of the lock,
+// so they can use the store. Only one caller delivers changes at a time, and changes
+// queued meanwhile, including the ones made by listeners, are delivered by that caller.
+func (c *MemoryCredentialStore) notifyChangeListeners() {
+	c.mtx.Lock()
+	if c.dispatching {
+		c.mtx.Unlock()
+		return
+	}
+	c.dispatching = true
+
+	for len(c.pending) > 0 {
+		notification := c.pending[0]
+		c.pending = c.pending[1:]
+		listeners := make([]crun.INotifiable, len(c.listeners))
+		copy(listeners, c.listeners)
+		c.mtx.Unlock()
+
+		change := notification.change
+		for _, listener := range listeners {
+			args := crun.NewParametersFromTuples(
+				"key", change.Key,
+				"version", change.Version,
+			)
+			args.Put(CredentialChangeArgKey, change)
+			listener.Notify(notification.ctx, notification.correlationId, args)
+		}
+
+		c.mtx.Lock()
+	}
+
+	c.dispatching = false
+	c.mtx.Unlock()
+}
@@ -0,0 +1,332 @@
+package config
+
+import (
+	"context"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	cconfig "github.com/pip-services3-gox/pip-services3-commons-gox/config"
+	"github.com/pip-services3-gox/pip-services3-commons-gox/errors"
+	crun "github.com/pip-services3-gox/pip-services3-commons-gox/run"
+)
+
+// ConfigReaderFactoryFunc is a function that creates a config reader for a file with the given path.
+type ConfigReaderFactoryFunc func(path string) IConfigReader
+
+var configReaderExtensions = map[string]ConfigReaderFactoryFunc{
+	".json":       func(path string) IConfigReader { return NewJsonConfigReader(path) },
+	".yml":        func(path string) IConfigReader { return NewYamlConfigReader(path) },
+	".yaml":       func(path string) IConfigReader { return NewYamlConfigReader(path) },
+	".toml":       func(path string) IConfigReader { return NewTomlConfigReader(path) },
+	".properties": func(path string) IConfigReader { return NewPropertiesConfigReader(path) },
+}
+var configReaderExtensionsMtx sync.RWMutex
+
+// RegisterConfigReaderExtension registers a config reader for files with the given extension.
+// A registered reader replaces a previous one for the same extension.
+//	Parameters:
+//		- extension string a file extension like ".json". The leading dot is optional and the case is ignored.
+//		- factory ConfigReaderFactoryFunc a function to create the reader or nil to remove the extension.
+func RegisterConfigReaderExtension(extension string, factory ConfigReaderFactoryFunc) {
+	extension = normalizeConfigExtension(extension)
+
+	configReaderExtensionsMtx.Lock()
+	defer configReaderExtensionsMtx.Unlock()
+
+	if factory == nil {
+		delete(configReaderExtensions, extension)
+	} else {
+		configReaderExtensions[extension] = factory
+	}
+}
+
+// ConfigReaderExtensions gets file extensions with registered config readers.
+//	Returns: []string the sorted list of extensions.
+func ConfigReaderExtensions() []string {
+	configReaderExtensionsMtx.RLock()
+	defer configReaderExtensionsMtx.RUnlock()
+
+	result := make([]string, 0, len(configReaderExtensions))
+	for extension := range configReaderExtensions {
+		result = append(result, extension)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// NewConfigReaderForFile creates a config reader selected by the file extension.
+// Supported extensions are .json, .yml, .yaml, .toml, .properties and any registered extras.
+//	see RegisterConfigReaderExtension
+//	Parameters:
+//		- correlationId string transaction id to trace execution through call chain.
+//		- path string a path to configuration file.
+//	Returns: IConfigReader, error the config reader or error if the extension is not supported.
+func NewConfigReaderForFile(correlationId string, path string) (IConfigReader, error) {
+	if path == "" {
+		return nil, errors.NewConfigError(correlationId, "NO_PATH", "Missing config file path")
+	}
+
+	extension := normalizeConfigExtension(filepath.Ext(path))
+
+	configReaderExtensionsMtx.RLock()
+	factory, ok := configReaderExtensions[extension]
+	configReaderExtensionsMtx.RUnlock()
+
+	if !ok {
+		return nil, errors.NewConfigError(
+			correlationId,
+			"UNSUPPORTED_FORMAT",
+			"Configuration format of "+path+" is not supported",
+		).WithDetails("path", path).WithDetails("extension", extension)
+	}
+	return factory(path), nil
+}
+
+// ReadConfig reads configuration from a file in any supported format,
+// parameterize it with given values and returns a new ConfigParams object.
+// The format is selected by the file extension.
+//	see NewConfigReaderForFile
+//	Parameters:
+//		- ctx context.Context
+//		- correlationId string transaction id to trace execution through call chain.
+//		- path string
+//		- parameters *cconfig.ConfigParams values to parameters the configuration.
+//	Returns: *cconfig.ConfigParams, error
+func ReadConfig(ctx context.Context, correlationId string, path string,
+	parameters *cconfig.ConfigParams) (*cconfig.ConfigParams, error) {
+
+	reader, err := NewConfigReaderForFile(correlationId, path)
+	if err != nil {
+		return nil, err
+	}
+	return reader.ReadConfig(ctx, correlationId, parameters)
+}
+
+func normalizeConfigExtension(extension string) string {
+	extension = strings.ToLower(extension)
+	if extension != "" && !strings.HasPrefix(extension, ".") {
+		extension = "." + extension
+	}
+	return extension
+}
+
+// AutoFileConfigReader is a config reader that reads configuration from a file
+// in the format selected by the file extension.
+//	Configuration parameters:
+//		- path: path to configuration file
+//		- parameters: this entire section is used as template parameters
+//		...
+//	see IConfigReader
+//	see NewConfigReaderForFile
+//	Example:
+//		configReader := NewAutoFileConfigReader("config.toml")
+//		parameters := NewConfigParamsFromTuples("KEY1_VALUE", 123, "KEY2_VALUE", "ABC");
+//		res, err := configReader.ReadConfig(context.Background(), "123", parameters);
+type AutoFileConfigReader struct {
+	*ConfigReader
+	path       string
+	config     *cconfig.ConfigParams
+	reader     IConfigReader
+	readerPath string
+	listening  bool
+	forwarder  *changeListenerForwarder
+	mtx        sync.Mutex
+}
+
+// NewEmptyAutoFileConfigReader creates a new instance of the config reader.
+//	Returns: *AutoFileConfigReader
+func NewEmptyAutoFileConfigReader() *AutoFileConfigReader {
+	return NewAutoFileConfigReader("")
+}
+
+// NewAutoFileConfigReader creates a new instance of the config reader.
+//	Parameters: path string a path to configuration file.
+//	Returns: *AutoFileConfigReader
+func NewAutoFileConfigReader(path string) *AutoFileConfigReader {
+	c := &AutoFileConfigReader{
+		ConfigReader: NewConfigReader(),
+		path:         path,
+		config:       cconfig.NewEmptyConfigParams(),
+	}
+	c.forwarder = &changeListenerForwarder{target: c.ConfigReader}
+	c.trackChanges(c)
+	return c
+}
+
+// Configure component by passing configuration parameters.
+// The configuration is also passed to the underlying reader.
+//	Parameters:
+//		- ctx context.Context
+//		- config *cconfig.ConfigParams configuration parameters to be set.
+func (c *AutoFileConfigReader) Configure(ctx context.Context, config *cconfig.ConfigParams) {
+	c.ConfigReader.Configure(ctx, config)
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.config = config
+	c.path = config.GetAsStringWithDefault(FileConfigReaderPathKey, c.path)
+	if configurable, ok := c.reader.(cconfig.IConfigurable); ok && c.readerPath == c.path {
+		configurable.Configure(ctx, config)
+	}
+}
+
+// Path get the path to configuration file.
+//	Returns: string the path to configuration file.
+func (c *AutoFileConfigReader) Path() string {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	return c.path
+}
+
+// SetPath set the path to configuration file.
+//	Parameters:
+//		- path string a new path to configuration file.
+func (c *AutoFileConfigReader) SetPath(path string) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.path = path
+}
+
+// Reader gets the underlying config reader selected by the file extension.
+//	Parameters:
+//		- ctx context.Context
+//		- correlationId string transaction id to trace execution through call chain.
+//	Returns: IConfigReader, error the config reader or error if the file format is not supported.
+func (c *AutoFileConfigReader) Reader(ctx context.Context, correlationId string) (IConfigReader, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	return c.selectReader(ctx, correlationId)
+}
+
+func (c *AutoFileConfigReader) selectReader(ctx context.Context, correlationId string) (IConfigReader, error) {
+	if c.reader != nil && c.readerPath == c.path {
+		return c.reader, nil
+	}
+
+	reader, err := NewConfigReaderForFile(correlationId, c.path)
+	if err != nil {
+		return nil, err
+	}
+
+	if configurable, ok := reader.(cconfig.IConfigurable); ok {
+		configurable.Configure(ctx, c.config)
+	}
+	if r, ok := reader.(interface{ SetPath(path string) }); ok {
+		r.SetPath(c.path)
+	}
+
+	if c.reader != nil && c.listening {
+		c.reader.RemoveChangeListener(ctx, c.forwarder)
+	}
+	if c.listening {
+		reader.AddChangeListener(ctx, c.forwarder)
+	}
+
+	c.reader = reader
+	c.readerPath = c.path
+	return reader, nil
+}
+
+// ReadConfig reads configuration from a file using the reader selected by the file extension,
+// parameterize it with given values and returns a new ConfigParams object.
+//	Parameters:
+//		- ctx context.Context
+//		- correlationId string transaction id to trace execution through call chain.
+//		- parameters *cconfig.ConfigParams values to parameters the configuration.
+//	Returns: *cconfig.ConfigParams, error
+func (c *AutoFileConfigReader) ReadConfig(ctx context.Context, correlationId string,
+	parameters *cconfig.ConfigParams) (*cconfig.ConfigParams, error) {
+
+	reader, err := c.Reader(ctx, correlationId)
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := reader.ReadConfig(ctx, correlationId, parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	if err = c.ValidateConfig(correlationId, config); err != nil {
+		return nil, err
+	}
+	c.rememberConfig(parameters, config)
+	return config, nil
+}
+
+// ReadConfigWithSources reads configuration from a file using the reader selected by the file extension
+// and returns sources of configuration values with files and lines they came from.
+//	Parameters:
+//		- ctx context.Context
+//		- correlationId string transaction id to trace execution through call chain.
+//		- parameters *cconfig.ConfigParams values to parameters the configuration.
+//	Returns: *cconfig.ConfigParams, map[string]*ConfigSource, error
+func (c *AutoFileConfigReader) ReadConfigWithSources(ctx context.Context, correlationId string,
+	parameters *cconfig.ConfigParams) (*cconfig.ConfigParams, map[string]*ConfigSource, error) {
+
+	reader, err := c.Reader(ctx, correlationId)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	config, sources, err := ReadConfigWithSources(ctx, correlationId, reader, parameters)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err = c.ValidateConfig(correlationId, config); err != nil {
+		return nil, nil, err
+	}
+	return config, sources, nil
+}
+
+// AddChangeListener adds a listener that will be notified when configuration is changed.
+//	Parameters:
+//		- ctx context.Context
+//		- listener crun.INotifiable a listener to be added.
+func (c *AutoFileConfigReader) AddChangeListener(ctx context.Context, listener crun.INotifiable) {
+	c.ConfigReader.AddChangeListener(ctx, listener)
+	if c.ChangeListenersCount() == 0 {
+		return
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if !c.listening {
+		c.listening = true
+		if c.reader != nil {
+			c.reader.AddChangeListener(ctx, c.forwarder)
+		} else {
+			// The reader subscribes the forwarder when it is selected
+			_, _ = c.selectReader(ctx, "")
+		}
+	}
+}
+
+// RemoveChangeListener removes a previously added change listener.
+//	Parameters:
+//		- ctx context.Context
+//		- listener crun.INotifiable a listener to be removed.
+func (c *AutoFileConfigReader) RemoveChangeListener(ctx context.Context, listener crun.INotifiable) {
+	c.ConfigReader.RemoveChangeListener(ctx, listener)
+	if c.ChangeListenersCount() > 0 {
+		return
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if c.listening {
+		c.listening = false
+		if c.reader != nil {
+			c.reader.RemoveChangeListener(ctx, c.forwarder)
+		}
+	}
+}
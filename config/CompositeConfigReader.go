@@ -0,0 +1,313 @@
+package config
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"sync"
+
+	cconfig "github.com/pip-services3-gox/pip-services3-commons-gox/config"
+	"github.com/pip-services3-gox/pip-services3-commons-gox/refer"
+	crun "github.com/pip-services3-gox/pip-services3-commons-gox/run"
+)
+
+// CompositeConfigReader is a config reader that aggregates multiple configuration sources
+// and merges their configurations in the order the readers were added.
+// Values from the readers added later override values from the readers added earlier,
+// so typical layers are defaults, environment-specific file, environment variables
+// and command-line overrides. Change notifications from any layer are propagated
+// to listeners of the composite reader.
+// Readers found in references are added in the order of descriptors listed in readers parameter.
+// Without it referenced readers are ordered by their descriptors, so the order is deterministic,
+// but readers parameter shall be used to define precedence of the layers.
+//	Configuration parameters:
+//		- readers: descriptors of referenced config readers in the order of increasing precedence,
+//		  like readers.0=*:config-reader:yaml:defaults:*, readers.1=*:config-reader:env:*:*
+//		- parameters: this entire section is used as template parameters
+//	References:
+//		- *:config-reader:*:*:1.0 (optional) IConfigReader components to read configuration from
+//	see IConfigReader
+//	Example:
+//		configReader := NewCompositeConfigReader(
+//			NewYamlConfigReader("defaults.yml"),
+//			NewYamlConfigReader("production.yml"),
+//			NewEnvConfigReader("APP"),
+//			NewMemoryConfigReader(commandLineConfig),
+//		)
+//		res, err := configReader.ReadConfig(context.Background(), "123", nil)
+type CompositeConfigReader struct {
+	*ConfigReader
+	readers   []IConfigReader
+	locators  []*refer.Descriptor
+	configErr error
+	listening bool
+	mtx       sync.Mutex
+	listener  *changeListenerForwarder
+}
+
+const (
+	// CompositeConfigReaderReadersKey is a constant for readers key
+	CompositeConfigReaderReadersKey = "readers"
+)
+
+// NewCompositeConfigReader creates a new instance of the config reader.
+//	Parameters:
+//		- readers ...IConfigReader config readers in the order of increasing precedence.
+//	Returns: *CompositeConfigReader
+func NewCompositeConfigReader(readers ...IConfigReader) *CompositeConfigReader {
+	c := &CompositeConfigReader{
+		ConfigReader: NewConfigReader(),
+		readers:      make([]IConfigReader, 0, len(readers)),
+	}
+	c.listener = &changeListenerForwarder{target: c.ConfigReader}
+	c.trackChanges(c)
+	for _, reader := range readers {
+		c.AddReader(context.Background(), reader)
+	}
+	return c
+}
+
+// NewEmptyCompositeConfigReader creates a new instance of the config reader without layers.
+//	Returns: *CompositeConfigReader
+func NewEmptyCompositeConfigReader() *CompositeConfigReader {
+	return NewCompositeConfigReader()
+}
+
+// Configure component by passing configuration parameters.
+// Invalid reader descriptors are reported when configuration is read.
+//	Parameters:
+//		- ctx context.Context
+//		- config *cconfig.ConfigParams configuration parameters to be set.
+func (c *CompositeConfigReader) Configure(ctx context.Context, config *cconfig.ConfigParams) {
+	c.ConfigReader.Configure(ctx, config)
+
+	locators := make([]*refer.Descriptor, 0)
+	var configErr error
+	for index := 0; ; index++ {
+		key := CompositeConfigReaderReadersKey + "." + strconv.Itoa(index)
+		value, ok := config.GetAsNullableString(key)
+		if !ok {
+			break
+		}
+		locator, err := refer.ParseDescriptorFromString(value)
+		if err != nil {
+			configErr = err
+			continue
+		}
+		if locator != nil {
+			locators = append(locators, locator)
+		}
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.locators = locators
+	c.configErr = configErr
+}
+
+// SetReferences sets references to dependent components.
+// Referenced config readers are added as layers in the order of configured descriptors,
+// or ordered by their descriptors when the order is not configured.
+//	Parameters:
+//		- ctx context.Context
+//		- references refer.IReferences references to locate the component dependencies.
+func (c *CompositeConfigReader) SetReferences(ctx context.Context, references refer.IReferences) {
+	c.mtx.Lock()
+	locators := c.locators
+	c.mtx.Unlock()
+
+	if len(locators) > 0 {
+		for _, locator := range locators {
+			for _, r := range references.GetOptional(locator) {
+				c.addReferencedReader(ctx, r)
+			}
+		}
+		return
+	}
+
+	// Without configured order readers are sorted by descriptors to keep layers deterministic
+	type referencedReader struct {
+		locator   string
+		component any
+	}
+	descriptor := refer.NewDescriptor("*", "config-reader", "*", "*", "*")
+	components := references.GetAll()
+	readers := make([]referencedReader, 0)
+	for index, locator := range references.GetAllLocators() {
+		if locator, ok := locator.(*refer.Descriptor); ok && index < len(components) && descriptor.Match(locator) {
+			readers = append(readers, referencedReader{locator: locator.String(), component: components[index]})
+		}
+	}
+	sort.SliceStable(readers, func(i, j int) bool {
+		return readers[i].locator < readers[j].locator
+	})
+	for _, reader := range readers {
+		c.addReferencedReader(ctx, reader.component)
+	}
+}
+
+// addReferencedReader adds a referenced component as a layer when it is a config reader.
+func (c *CompositeConfigReader) addReferencedReader(ctx context.Context, component any) {
+	if component == c {
+		return
+	}
+	if reader, ok := component.(IConfigReader); ok {
+		c.AddReader(ctx, reader)
+	}
+}
+
+// AddReader adds a config reader as a new layer with the highest precedence.
+//	Parameters:
+//		- ctx context.Context
+//		- reader IConfigReader a config reader to be added.
+func (c *CompositeConfigReader) AddReader(ctx context.Context, reader IConfigReader) {
+	if reader == nil {
+		return
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	for _, r := range c.readers {
+		if r == reader {
+			return
+		}
+	}
+	c.readers = append(c.readers, reader)
+
+	if c.listening {
+		reader.AddChangeListener(ctx, c.listener)
+	}
+}
+
+// Readers gets the config readers in the order of increasing precedence.
+//	Returns: []IConfigReader the config readers.
+func (c *CompositeConfigReader) Readers() []IConfigReader {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	result := make([]IConfigReader, len(c.readers))
+	copy(result, c.readers)
+	return result
+}
+
+// ReadConfig reads configuration from all layers, parameterize it with given values
+// and merges the results. Values from later layers override values from earlier ones.
+//	Parameters:
+//		- ctx context.Context
+//		- correlationId string transaction id to trace execution through call chain.
+//		- parameters *cconfig.ConfigParams values to parameters the configuration.
+//	Returns: *cconfig.ConfigParams, error
+func (c *CompositeConfigReader) ReadConfig(ctx context.Context, correlationId string,
+	parameters *cconfig.ConfigParams) (*cconfig.ConfigParams, error) {
+
+	config, _, err := c.readLayers(ctx, correlationId, parameters, false)
+	if err != nil {
+		return nil, err
+	}
+	c.rememberConfig(parameters, config)
+	return config, nil
+}
+
+// ReadConfigWithSources reads configuration from all layers, parameterize it with given values
+// and returns sources of configuration values from the layers where they were finally defined.
+//	Parameters:
+//		- ctx context.Context
+//		- correlationId string transaction id to trace execution through call chain.
+//		- parameters *cconfig.ConfigParams values to parameters the configuration.
+//	Returns: *cconfig.ConfigParams, map[string]*ConfigSource, error
+func (c *CompositeConfigReader) ReadConfigWithSources(ctx context.Context, correlationId string,
+	parameters *cconfig.ConfigParams) (*cconfig.ConfigParams, map[string]*ConfigSource, error) {
+
+	return c.readLayers(ctx, correlationId, parameters, true)
+}
+
+func (c *CompositeConfigReader) readLayers(ctx context.Context, correlationId string,
+	parameters *cconfig.ConfigParams, withSources bool) (*cconfig.ConfigParams, map[string]*ConfigSource, error) {
+
+	c.mtx.Lock()
+	configErr := c.configErr
+	c.mtx.Unlock()
+	if configErr != nil {
+		return nil, nil, configErr
+	}
+
+	if parameters == nil {
+		parameters = cconfig.NewEmptyConfigParams()
+	}
+	parameters = c.ConfigReader.parameters.Override(parameters)
+
+	result := cconfig.NewEmptyConfigParams()
+	var sources map[string]*ConfigSource
+	if withSources {
+		sources = make(map[string]*ConfigSource)
+	}
+
+	for _, reader := range c.Readers() {
+		var config *cconfig.ConfigParams
+		var layerSources map[string]*ConfigSource
+		var err error
+		if withSources {
+			config, layerSources, err = ReadConfigWithSources(ctx, correlationId, reader, parameters)
+		} else {
+			config, err = reader.ReadConfig(ctx, correlationId, parameters)
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		if config != nil {
+			result = result.Override(config)
+		}
+		for key, source := range layerSources {
+			sources[key] = source
+		}
+	}
+
+	if err := c.ValidateConfig(correlationId, result); err != nil {
+		return nil, nil, err
+	}
+	return result, sources, nil
+}
+
+// AddChangeListener adds a listener that will be notified when configuration in any layer is changed.
+//	Parameters:
+//		- ctx context.Context
+//		- listener crun.INotifiable a listener to be added.
+func (c *CompositeConfigReader) AddChangeListener(ctx context.Context, listener crun.INotifiable) {
+	c.ConfigReader.AddChangeListener(ctx, listener)
+	if c.ChangeListenersCount() == 0 {
+		return
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if !c.listening {
+		c.listening = true
+		for _, reader := range c.readers {
+			reader.AddChangeListener(ctx, c.listener)
+		}
+	}
+}
+
+// RemoveChangeListener removes a previously added change listener.
+//	Parameters:
+//		- ctx context.Context
+//		- listener crun.INotifiable a listener to be removed.
+func (c *CompositeConfigReader) RemoveChangeListener(ctx context.Context, listener crun.INotifiable) {
+	c.ConfigReader.RemoveChangeListener(ctx, listener)
+	if c.ChangeListenersCount() > 0 {
+		return
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if c.listening {
+		c.listening = false
+		for _, reader := range c.readers {
+			reader.RemoveChangeListener(ctx, c.listener)
+		}
+	}
+}
@@ -0,0 +1,404 @@
+package config
+
+import (
+	"encoding"
+	"fmt"
+	refl "reflect"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	cconfig "github.com/pip-services3-gox/pip-services3-commons-gox/config"
+	"github.com/pip-services3-gox/pip-services3-commons-gox/errors"
+)
+
+// BindConfig populates fields of a Go struct from configuration parameters using struct tags.
+// It can be used from any Configure implementation instead of reading every field manually.
+//
+// Fields are bound using the following tags:
+//   - config:"key" sets the configuration key. Without the tag the field name converted
+//     to snake case is used, like "max_retries" for MaxRetries. config:"-" skips the field.
+//   - config:"key,required" reports an error when the key is missing.
+//   - default:"value" sets a value used when the key is missing.
+//
+// Supported field types are strings, booleans, integers, floats, time.Duration, time.Time,
+// types that implement encoding.TextUnmarshaler, slices, maps with string keys, nested structs
+// and pointers to them. Durations accept Go duration strings like "1m30s" or integer values
+// in milliseconds, and times accept RFC 3339 values like "2024-01-02T15:04:05Z" or dates like "2024-01-02".
+// Nested structs are bound from configuration sections, and fields of embedded structs without
+// config tags are bound as fields of the embedding struct. Slices are bound from indexed sections
+// like "hosts.0" or from comma-separated values. Fields without configuration values and defaults
+// keep their values.
+//	Parameters:
+//		- correlationId string transaction id to trace execution through call chain.
+//		- config *cconfig.ConfigParams configuration parameters to bind.
+//		- target any a pointer to a struct to be populated.
+//	Returns: error a ConfigError that lists every binding error or nil if binding succeeded.
+//	Example:
+//		type ConnectionConfig struct {
+//			Host    string        `config:"host,required"`
+//			Port    int           `config:"port" default:"8080"`
+//			Timeout time.Duration `config:"timeout" default:"30s"`
+//		}
+//
+//		type ServiceConfig struct {
+//			Connection ConnectionConfig `config:"connection"`
+//			MaxRetries int              `default:"3"`
+//		}
+//
+//		func (c *MyService) Configure(ctx context.Context, config *cconfig.ConfigParams) {
+//			err := BindConfig("123", config, &c.config)
+//			...
+//		}
+func BindConfig(correlationId string, config *cconfig.ConfigParams, target any) error {
+	value := refl.ValueOf(target)
+	if value.Kind() != refl.Pointer || value.IsNil() || value.Elem().Kind() != refl.Struct {
+		return errors.NewConfigError(
+			correlationId,
+			"INVALID_TARGET",
+			fmt.Sprintf("Config binding target must be a pointer to struct, but %T was given", target),
+		)
+	}
+
+	if config == nil {
+		config = cconfig.NewEmptyConfigParams()
+	}
+
+	messages := make([]string, 0)
+	bindConfigStruct(config, "", value.Elem(), &messages)
+	if len(messages) == 0 {
+		return nil
+	}
+
+	return errors.NewConfigError(
+		correlationId,
+		"BIND_FAILED",
+		"Failed to bind configuration: "+strings.Join(messages, "; "),
+	).WithDetails("errors", messages)
+}
+
+// bindConfigStruct binds exported struct fields from the configuration.
+func bindConfigStruct(config *cconfig.ConfigParams, prefix string, value refl.Value, messages *[]string) {
+	typ := value.Type()
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.Anonymous && field.Tag.Get("config") == "" {
+			if embedded, ok := embeddedConfigStruct(field, value.Field(i)); ok {
+				bindConfigStruct(config, prefix, embedded, messages)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+
+		name, required, skip := parseConfigTag(field)
+		if skip {
+			continue
+		}
+		key := name
+		if prefix != "" {
+			key = prefix + "." + name
+		}
+
+		defaultValue, hasDefault := field.Tag.Lookup("default")
+		bindConfigField(config, key, value.Field(i), required, defaultValue, hasDefault, messages)
+	}
+}
+
+// embeddedConfigStruct gets an embedded struct whose fields are bound as fields of the embedding struct.
+// Nil pointers to exported embedded structs are allocated.
+func embeddedConfigStruct(field refl.StructField, value refl.Value) (refl.Value, bool) {
+	typ := field.Type
+	if typ.Kind() == refl.Pointer {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != refl.Struct || isConfigValueType(typ) {
+		return refl.Value{}, false
+	}
+
+	if value.Kind() == refl.Pointer {
+		if value.IsNil() {
+			if !field.IsExported() {
+				return refl.Value{}, false
+			}
+			value.Set(refl.New(typ))
+		}
+		value = value.Elem()
+	}
+	return value, true
+}
+
+// bindConfigField binds a single field from the configuration key or its section.
+func bindConfigField(config *cconfig.ConfigParams, key string, field refl.Value,
+	required bool, defaultValue string, hasDefault bool, messages *[]string) {
+
+	// Nested structs are always bound to apply their defaults and check required keys
+	if field.Kind() == refl.Struct && !isConfigValueType(field.Type()) {
+		bindConfigStruct(config, key, field, messages)
+		return
+	}
+
+	if isConfigSectionType(field.Type()) && config.GetSection(key).Len() > 0 {
+		if err := setConfigSection(config, key, field, messages); err != nil {
+			*messages = append(*messages, key+": "+err.Error())
+		}
+		return
+	}
+
+	text, ok := config.GetAsNullableString(key)
+	if !ok {
+		if !hasDefault {
+			if required {
+				*messages = append(*messages, key+" is required")
+			}
+			return
+		}
+		text = defaultValue
+	}
+
+	if err := setConfigValue(field, text); err != nil {
+		*messages = append(*messages, key+": "+err.Error())
+	}
+}
+
+// parseConfigTag gets configuration key and options of the field.
+func parseConfigTag(field refl.StructField) (name string, required bool, skip bool) {
+	tag := field.Tag.Get("config")
+	if tag == "-" {
+		return "", false, true
+	}
+
+	parts := strings.Split(tag, ",")
+	name = strings.TrimSpace(parts[0])
+	for _, option := range parts[1:] {
+		if strings.TrimSpace(option) == "required" {
+			required = true
+		}
+	}
+	if name == "" {
+		name = toConfigKey(field.Name)
+	}
+	return name, required, false
+}
+
+// toConfigKey converts a field name like MaxRetries or HTTPPort into snake case key.
+func toConfigKey(name string) string {
+	runes := []rune(name)
+	builder := strings.Builder{}
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) ||
+				(i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))) {
+				builder.WriteRune('_')
+			}
+			builder.WriteRune(unicode.ToLower(r))
+		} else {
+			builder.WriteRune(r)
+		}
+	}
+	return builder.String()
+}
+
+var durationType = refl.TypeOf(time.Duration(0))
+var timeType = refl.TypeOf(time.Time{})
+var textUnmarshalerType = refl.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+
+// isConfigValueType checks if the type is bound from a single value even when it is a struct,
+// like time.Time or types that implement encoding.TextUnmarshaler.
+func isConfigValueType(typ refl.Type) bool {
+	return typ == timeType || refl.PointerTo(typ).Implements(textUnmarshalerType)
+}
+
+// isConfigSectionType checks if the type is bound from a configuration section.
+func isConfigSectionType(typ refl.Type) bool {
+	for typ.Kind() == refl.Pointer {
+		typ = typ.Elem()
+	}
+	if isConfigValueType(typ) {
+		return false
+	}
+	switch typ.Kind() {
+	case refl.Struct, refl.Map:
+		return true
+	case refl.Slice:
+		return typ.Elem().Kind() != refl.Uint8
+	}
+	return false
+}
+
+// setConfigSection binds structs, maps and slices from the configuration section.
+func setConfigSection(config *cconfig.ConfigParams, key string, field refl.Value, messages *[]string) error {
+	if field.Kind() == refl.Pointer {
+		if field.IsNil() {
+			field.Set(refl.New(field.Type().Elem()))
+		}
+		return setConfigSection(config, key, field.Elem(), messages)
+	}
+
+	section := config.GetSection(key)
+	switch field.Kind() {
+	case refl.Struct:
+		bindConfigStruct(config, key, field, messages)
+		return nil
+
+	case refl.Map:
+		if field.Type().Key().Kind() != refl.String {
+			return fmt.Errorf("unsupported map key type %s", field.Type().Key())
+		}
+		if field.IsNil() {
+			field.Set(refl.MakeMap(field.Type()))
+		}
+		for _, name := range configSectionNames(section) {
+			item := refl.New(field.Type().Elem()).Elem()
+			bindConfigField(config, key+"."+name, item, false, "", false, messages)
+			field.SetMapIndex(refl.ValueOf(name).Convert(field.Type().Key()), item)
+		}
+		return nil
+
+	case refl.Slice:
+		names := configSectionNames(section)
+		items := refl.MakeSlice(field.Type(), 0, len(names))
+		for index := 0; ; index++ {
+			name := strconv.Itoa(index)
+			if _, ok := section.GetAsNullableString(name); !ok && section.GetSection(name).Len() == 0 {
+				break
+			}
+			item := refl.New(field.Type().Elem()).Elem()
+			bindConfigField(config, key+"."+name, item, false, "", false, messages)
+			items = refl.Append(items, item)
+		}
+		field.Set(items)
+		return nil
+	}
+	return fmt.Errorf("unsupported type %s", field.Type())
+}
+
+// configSectionNames gets names of direct subsections and keys of the section.
+func configSectionNames(section *cconfig.ConfigParams) []string {
+	result := make([]string, 0)
+	found := make(map[string]bool)
+	for _, key := range section.Keys() {
+		if pos := strings.Index(key, "."); pos > 0 {
+			key = key[:pos]
+		}
+		if !found[key] {
+			found[key] = true
+			result = append(result, key)
+		}
+	}
+	return result
+}
+
+// setConfigValue converts configuration text into the field type.
+func setConfigValue(field refl.Value, text string) error {
+	if field.Kind() == refl.Pointer {
+		item := refl.New(field.Type().Elem())
+		if err := setConfigValue(item.Elem(), text); err != nil {
+			return err
+		}
+		field.Set(item)
+		return nil
+	}
+
+	if field.Type() == durationType {
+		duration, err := parseConfigDuration(text)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(duration))
+		return nil
+	}
+
+	if field.Type() == timeType {
+		value, err := parseConfigTime(text)
+		if err != nil {
+			return err
+		}
+		field.Set(refl.ValueOf(value))
+		return nil
+	}
+
+	if field.CanAddr() && field.Addr().Type().Implements(textUnmarshalerType) {
+		unmarshaler := field.Addr().Interface().(encoding.TextUnmarshaler)
+		if err := unmarshaler.UnmarshalText([]byte(strings.TrimSpace(text))); err != nil {
+			return fmt.Errorf("invalid %s value %q: %s", field.Type(), text, err.Error())
+		}
+		return nil
+	}
+
+	switch field.Kind() {
+	case refl.String:
+		field.SetString(text)
+	case refl.Bool:
+		value, err := strconv.ParseBool(strings.TrimSpace(text))
+		if err != nil {
+			return fmt.Errorf("invalid boolean value %q", text)
+		}
+		field.SetBool(value)
+	case refl.Int, refl.Int8, refl.Int16, refl.Int32, refl.Int64:
+		value, err := strconv.ParseInt(strings.TrimSpace(text), 10, field.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid integer value %q", text)
+		}
+		field.SetInt(value)
+	case refl.Uint, refl.Uint8, refl.Uint16, refl.Uint32, refl.Uint64:
+		value, err := strconv.ParseUint(strings.TrimSpace(text), 10, field.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid unsigned integer value %q", text)
+		}
+		field.SetUint(value)
+	case refl.Float32, refl.Float64:
+		value, err := strconv.ParseFloat(strings.TrimSpace(text), field.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("invalid float value %q", text)
+		}
+		field.SetFloat(value)
+	case refl.Slice:
+		if field.Type().Elem().Kind() == refl.Uint8 {
+			field.SetBytes([]byte(text))
+			return nil
+		}
+		parts := make([]string, 0)
+		if strings.TrimSpace(text) != "" {
+			parts = strings.Split(text, ",")
+		}
+		items := refl.MakeSlice(field.Type(), 0, len(parts))
+		for _, part := range parts {
+			item := refl.New(field.Type().Elem()).Elem()
+			if err := setConfigValue(item, strings.TrimSpace(part)); err != nil {
+				return err
+			}
+			items = refl.Append(items, item)
+		}
+		field.Set(items)
+	default:
+		return fmt.Errorf("unsupported type %s", field.Type())
+	}
+	return nil
+}
+
+// parseConfigTime parses RFC 3339 times or dates.
+func parseConfigTime(text string) (time.Time, error) {
+	text = strings.TrimSpace(text)
+	for _, layout := range []string{time.RFC3339Nano, "2006-01-02"} {
+		if value, err := time.Parse(layout, text); err == nil {
+			return value, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid time value %q", text)
+}
+
+// parseConfigDuration parses Go duration strings or integer values in milliseconds.
+func parseConfigDuration(text string) (time.Duration, error) {
+	text = strings.TrimSpace(text)
+	if millis, err := strconv.ParseInt(text, 10, 64); err == nil {
+		return time.Duration(millis) * time.Millisecond, nil
+	}
+	duration, err := time.ParseDuration(text)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration value %q", text)
+	}
+	return duration, nil
+}
@@ -0,0 +1,195 @@
+package config
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+
+	cconfig "github.com/pip-services3-gox/pip-services3-commons-gox/config"
+	crun "github.com/pip-services3-gox/pip-services3-commons-gox/run"
+)
+
+// ConfigChangeArgKey is a key of notification arguments that holds *ConfigChange
+const ConfigChangeArgKey = "change"
+
+// ConfigChange describes differences between old and new configuration.
+// Components can use it to reconfigure only affected sections such as connection or options.
+//	see DiffConfig
+//	Example:
+//		change := DiffConfig(oldConfig, newConfig)
+//		if change.HasSection("connection") {
+//			reconnect(change.NewConfig.GetSection("connection"))
+//		}
+type ConfigChange struct {
+	OldConfig *cconfig.ConfigParams // Configuration before the change
+	NewConfig *cconfig.ConfigParams // Configuration after the change
+	Added     []string              // Sorted keys that are present only in the new configuration
+	Removed   []string              // Sorted keys that are present only in the old configuration
+	Changed   []string              // Sorted keys with different values
+}
+
+// DiffConfig compares two configurations and returns their differences.
+//	Parameters:
+//		- oldConfig *cconfig.ConfigParams configuration before the change or nil.
+//		- newConfig *cconfig.ConfigParams configuration after the change or nil.
+//	Returns: *ConfigChange the differences between configurations.
+func DiffConfig(oldConfig *cconfig.ConfigParams, newConfig *cconfig.ConfigParams) *ConfigChange {
+	if oldConfig == nil {
+		oldConfig = cconfig.NewEmptyConfigParams()
+	}
+	if newConfig == nil {
+		newConfig = cconfig.NewEmptyConfigParams()
+	}
+
+	change := &ConfigChange{
+		OldConfig: oldConfig,
+		NewConfig: newConfig,
+		Added:     make([]string, 0),
+		Removed:   make([]string, 0),
+		Changed:   make([]string, 0),
+	}
+
+	for _, key := range newConfig.Keys() {
+		newValue, _ := newConfig.GetAsNullableString(key)
+		oldValue, ok := oldConfig.GetAsNullableString(key)
+		if !ok {
+			change.Added = append(change.Added, key)
+		} else if oldValue != newValue {
+			change.Changed = append(change.Changed, key)
+		}
+	}
+	for _, key := range oldConfig.Keys() {
+		if _, ok := newConfig.GetAsNullableString(key); !ok {
+			change.Removed = append(change.Removed, key)
+		}
+	}
+
+	sort.Strings(change.Added)
+	sort.Strings(change.Removed)
+	sort.Strings(change.Changed)
+	return change
+}
+
+// IsEmpty checks if configurations are the same.
+//	Returns: bool true if there are no added, removed or changed keys.
+func (c *ConfigChange) IsEmpty() bool {
+	return len(c.Added) == 0 && len(c.Removed) == 0 && len(c.Changed) == 0
+}
+
+// Keys gets all added, removed and changed keys.
+//	Returns: []string the sorted list of affected keys.
+func (c *ConfigChange) Keys() []string {
+	result := make([]string, 0, len(c.Added)+len(c.Removed)+len(c.Changed))
+	result = append(result, c.Added...)
+	result = append(result, c.Removed...)
+	result = append(result, c.Changed...)
+	sort.Strings(result)
+	return result
+}
+
+// HasKey checks if the key was added, removed or changed.
+//	Parameters:
+//		- key string a configuration key.
+//	Returns: bool true if the key is affected by the change.
+func (c *ConfigChange) HasKey(key string) bool {
+	for _, k := range c.Keys() {
+		if k == key {
+			return true
+		}
+	}
+	return false
+}
+
+// HasSection checks if any key in the section was added, removed or changed.
+// Sections can be nested using dot notation like "connection" or "options.retries".
+//	Parameters:
+//		- section string a configuration section.
+//	Returns: bool true if the section is affected by the change.
+func (c *ConfigChange) HasSection(section string) bool {
+	prefix := section + "."
+	for _, key := range c.Keys() {
+		if key == section || strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Sections gets names of top-level sections affected by the change.
+//	Returns: []string the sorted list of affected sections.
+func (c *ConfigChange) Sections() []string {
+	sections := make(map[string]bool)
+	for _, key := range c.Keys() {
+		if pos := strings.Index(key, "."); pos > 0 {
+			key = key[:pos]
+		}
+		sections[key] = true
+	}
+
+	result := make([]string, 0, len(sections))
+	for section := range sections {
+		result = append(result, section)
+	}
+	sort.Strings(result)
+	return result
+}
+
+// ConfigChangeFromArgs gets configuration change from notification arguments.
+//	see ConfigChangeArgKey
+//	Parameters:
+//		- args *crun.Parameters notification arguments.
+//	Returns: *ConfigChange, bool the configuration change and true if it was found.
+func ConfigChangeFromArgs(args *crun.Parameters) (*ConfigChange, bool) {
+	if args == nil {
+		return nil, false
+	}
+	value, ok := args.Get(ConfigChangeArgKey)
+	if !ok {
+		return nil, false
+	}
+	change, ok := value.(*ConfigChange)
+	return change, ok && change != nil
+}
+
+// configChangeTracker remembers the last configuration read by a reader and its parameters,
+// so change notifications of the reader can carry differences with that configuration.
+type configChangeTracker struct {
+	reader     IConfigReader
+	parameters *cconfig.ConfigParams
+	config     *cconfig.ConfigParams
+	mtx        sync.Mutex
+	diffMtx    sync.Mutex
+}
+
+// remember keeps the configuration read with the given parameters.
+func (c *configChangeTracker) remember(parameters *cconfig.ConfigParams, config *cconfig.ConfigParams) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.parameters = parameters
+	c.config = config
+}
+
+// diff re-reads configuration with the last used parameters and compares it with the last read one.
+// Returns nil when configuration wasn't read before or cannot be read now.
+func (c *configChangeTracker) diff(ctx context.Context, correlationId string) *ConfigChange {
+	c.diffMtx.Lock()
+	defer c.diffMtx.Unlock()
+
+	c.mtx.Lock()
+	reader := c.reader
+	parameters := c.parameters
+	previous := c.config
+	c.mtx.Unlock()
+
+	if reader == nil || previous == nil {
+		return nil
+	}
+
+	config, err := reader.ReadConfig(ctx, correlationId, parameters)
+	if err != nil {
+		return nil
+	}
+	return DiffConfig(previous, config)
+}
@@ -2,21 +2,49 @@ package config
 
 import (
 	"context"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
 	cconfig "github.com/pip-services3-gox/pip-services3-commons-gox/config"
+	"github.com/pip-services3-gox/pip-services3-commons-gox/errors"
 	crun "github.com/pip-services3-gox/pip-services3-commons-gox/run"
+	"github.com/pip-services3-gox/pip-services3-commons-gox/validate"
 	"github.com/pip-services3-gox/pip-services3-expressions-gox/mustache"
+	mparsers "github.com/pip-services3-gox/pip-services3-expressions-gox/mustache/parsers"
 )
 
-// ConfigReader abstract config reader that supports configuration parameterization.
+// ConfigReader abstract config reader that supports configuration parameterization
+// and optional validation of the read configuration against a schema.
+// Template parameters may define default values using {{NAME:default}} syntax.
+// Process environment variables can be enabled as implicit parameters with the lowest precedence.
 //	Configuration parameters:
-//		parameters this entire section is used as template parameters
+//		- parameters: this entire section is used as template parameters
+//		- options:
+//			- strict: true to fail parameterization when template uses undefined parameters (default: false)
+//			- env_parameters: true to use environment variables as template parameters (default: false)
 type ConfigReader struct {
-	parameters *cconfig.ConfigParams
+	parameters    *cconfig.ConfigParams
+	strict        bool
+	envParameters bool
+	schema        validate.ISchema
+	listeners     []crun.INotifiable
+	listenersMtx  sync.Mutex
+	changes       configChangeTracker
 }
 
 // SectionNameParameters is a name of ConfigReader section
 const SectionNameParameters = "parameters"
 
+const (
+	// ConfigReaderStrictKey is a constant for strict parameterization key
+	ConfigReaderStrictKey = "options.strict"
+	// ConfigReaderEnvParametersKey is a constant for environment parameters key
+	ConfigReaderEnvParametersKey = "options.env_parameters"
+)
+
 // NewConfigReader creates a new instance of the config reader.
 //	Returns: *ConfigReader
 func NewConfigReader() *ConfigReader {
@@ -34,10 +62,41 @@ func (c *ConfigReader) Configure(ctx context.Context, config *cconfig.ConfigPara
 	if parameters.Len() > 0 {
 		c.parameters = parameters
 	}
+	c.strict = config.GetAsBooleanWithDefault(ConfigReaderStrictKey, c.strict)
+	c.envParameters = config.GetAsBooleanWithDefault(ConfigReaderEnvParametersKey, c.envParameters)
+}
+
+// Strict checks if parameterization fails when template uses undefined parameters.
+//	Returns: bool true if strict mode is enabled.
+func (c *ConfigReader) Strict() bool {
+	return c.strict
+}
+
+// SetStrict enables or disables strict parameterization.
+//	Parameters:
+//		- strict bool true to fail parameterization when template uses undefined parameters.
+func (c *ConfigReader) SetStrict(strict bool) {
+	c.strict = strict
+}
+
+// EnvParameters checks if environment variables are used as template parameters.
+//	Returns: bool true if environment variables are used.
+func (c *ConfigReader) EnvParameters() bool {
+	return c.envParameters
+}
+
+// SetEnvParameters enables or disables use of environment variables as template parameters.
+//	Parameters:
+//		- envParameters bool true to use environment variables as template parameters.
+func (c *ConfigReader) SetEnvParameters(envParameters bool) {
+	c.envParameters = envParameters
 }
 
 // Parameterize configuration template given as string with dynamic parameters.
-// The method uses Mustache template engine implemented in expressions module
+// The method uses Mustache template engine implemented in expressions module.
+// Parameters with default values like {{DB_HOST:localhost}} are replaced with defaults
+// when they are undefined or empty. In strict mode the method fails with the list
+// of undefined parameters used by the template.
 //	Parameters:
 //		- config string a string with configuration template to be parameterized
 //		- parameters *config.ConfigParams dynamic parameters to inject into the template
@@ -48,24 +107,209 @@ func (c *ConfigReader) Parameterize(config string, parameters *cconfig.ConfigPar
 	}
 
 	parameters = c.parameters.Override(parameters)
+	if c.envParameters {
+		parameters = environmentParameters().Override(parameters)
+	}
 
 	value := parameters.Value()
 
-	mustacheTemplate, err := mustache.NewMustacheTemplateFromString(config)
+	mustacheTemplate, err := mustache.NewMustacheTemplateFromString(expandParameterDefaults(config))
 	if err != nil {
 		return "", err
 	}
 
+	if c.strict {
+		missing := make(map[string]bool)
+		collectMissingParameters(mustacheTemplate, mustacheTemplate.ResultTokens(), value, missing)
+		if len(missing) > 0 {
+			names := make([]string, 0, len(missing))
+			for name := range missing {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+
+			return "", errors.NewConfigError(
+				"",
+				"MISSING_PARAMETERS",
+				"Configuration parameters are not defined: "+strings.Join(names, ", "),
+			).WithDetails("parameters", names)
+		}
+	}
+
 	result, err := mustacheTemplate.EvaluateWithVariables(value)
 	return result, err
 }
 
+var parameterDefaultPattern = regexp.MustCompile(`\{\{(\{?)\s*([A-Za-z_][A-Za-z0-9_.\-]*)\s*:([^{}]*?)(\}?)\}\}`)
+
+// expandParameterDefaults replaces {{NAME:default}} tags with standard mustache sections.
+func expandParameterDefaults(template string) string {
+	return parameterDefaultPattern.ReplaceAllStringFunc(template, func(match string) string {
+		groups := parameterDefaultPattern.FindStringSubmatch(match)
+		name, defaultValue := groups[2], strings.TrimSpace(groups[3])
+		variable := "{{" + name + "}}"
+		if groups[1] != "" && groups[4] != "" {
+			variable = "{{{" + name + "}}}"
+		}
+		return "{{^" + name + "}}" + defaultValue + "{{/" + name + "}}" +
+			"{{#" + name + "}}" + variable + "{{/" + name + "}}"
+	})
+}
+
+// collectMissingParameters collects names of undefined variables in the rendered parts of the template.
+func collectMissingParameters(template *mustache.MustacheTemplate, tokens []*mparsers.MustacheToken,
+	variables map[string]string, missing map[string]bool) {
+
+	for _, token := range tokens {
+		switch token.Type() {
+		case mparsers.TokenVariable, mparsers.TokenEscapedVariable:
+			if template.GetVariable(variables, token.Value()) == nil {
+				missing[token.Value()] = true
+			}
+		case mparsers.TokenSection:
+			value := template.GetVariable(variables, token.Value())
+			if value != nil && *value != "" {
+				collectMissingParameters(template, token.Tokens(), variables, missing)
+			}
+		case mparsers.TokenInvertedSection:
+			value := template.GetVariable(variables, token.Value())
+			if value == nil || *value == "" {
+				collectMissingParameters(template, token.Tokens(), variables, missing)
+			}
+		}
+	}
+}
+
+// environmentParameters gets process environment variables as configuration parameters.
+func environmentParameters() *cconfig.ConfigParams {
+	result := cconfig.NewEmptyConfigParams()
+	for _, variable := range os.Environ() {
+		if pos := strings.Index(variable, "="); pos > 0 {
+			result.Put(variable[:pos], variable[pos+1:])
+		}
+	}
+	return result
+}
+
+// Schema gets the schema to validate read configuration.
+//	Returns: validate.ISchema the validation schema or nil if validation is disabled.
+func (c *ConfigReader) Schema() validate.ISchema {
+	return c.schema
+}
+
+// SetSchema sets the schema to validate read configuration.
+//	Parameters:
+//		- schema validate.ISchema a validation schema or nil to disable validation.
+func (c *ConfigReader) SetSchema(schema validate.ISchema) {
+	c.schema = schema
+}
+
+// ValidateConfig validates configuration against the schema set in the reader.
+//	see ValidateConfig
+//	Parameters:
+//		- correlationId string transaction id to trace execution through call chain.
+//		- config *config.ConfigParams configuration to be validated.
+//	Returns: error a ConfigError that lists all violations or nil if configuration is valid.
+func (c *ConfigReader) ValidateConfig(correlationId string, config *cconfig.ConfigParams) error {
+	return ValidateConfig(correlationId, config, c.schema)
+}
+
 // AddChangeListener - Adds a listener that will be notified when configuration is changed
 func (c *ConfigReader) AddChangeListener(ctx context.Context, listener crun.INotifiable) {
-	// Do nothing...
+	if listener == nil {
+		return
+	}
+
+	c.listenersMtx.Lock()
+	defer c.listenersMtx.Unlock()
+
+	for _, l := range c.listeners {
+		if l == listener {
+			return
+		}
+	}
+	c.listeners = append(c.listeners, listener)
 }
 
 // RemoveChangeListener - Remove a previously added change listener.
 func (c *ConfigReader) RemoveChangeListener(ctx context.Context, listener crun.INotifiable) {
-	// Do nothing...
+	c.listenersMtx.Lock()
+	defer c.listenersMtx.Unlock()
+
+	for i, l := range c.listeners {
+		if l == listener {
+			c.listeners = append(c.listeners[:i], c.listeners[i+1:]...)
+			return
+		}
+	}
+}
+
+// ChangeListenersCount gets the number of registered change listeners.
+//	Returns: int the number of listeners.
+func (c *ConfigReader) ChangeListenersCount() int {
+	c.listenersMtx.Lock()
+	defer c.listenersMtx.Unlock()
+
+	return len(c.listeners)
+}
+
+// NotifyChangeListeners notifies all registered listeners that configuration was changed.
+// When the reader was read before and args don't carry *ConfigChange, configuration is re-read
+// with the last used parameters and its differences are added under ConfigChangeArgKey.
+//	Parameters:
+//		- ctx context.Context
+//		- correlationId string transaction id to trace execution through call chain.
+//		- args *crun.Parameters notification arguments.
+func (c *ConfigReader) NotifyChangeListeners(ctx context.Context, correlationId string, args *crun.Parameters) {
+	c.listenersMtx.Lock()
+	listeners := make([]crun.INotifiable, len(c.listeners))
+	copy(listeners, c.listeners)
+	c.listenersMtx.Unlock()
+
+	if len(listeners) == 0 {
+		return
+	}
+
+	if _, ok := ConfigChangeFromArgs(args); !ok {
+		if change := c.changes.diff(ctx, correlationId); change != nil {
+			if args == nil {
+				args = crun.NewEmptyParameters()
+			} else {
+				args = args.Clone()
+			}
+			args.Put(ConfigChangeArgKey, change)
+		}
+	}
+
+	for _, listener := range listeners {
+		listener.Notify(ctx, correlationId, args)
+	}
+}
+
+// trackChanges sets the reader used to re-read configuration when change listeners are notified.
+func (c *ConfigReader) trackChanges(reader IConfigReader) {
+	c.changes.mtx.Lock()
+	defer c.changes.mtx.Unlock()
+
+	c.changes.reader = reader
+}
+
+// rememberConfig keeps the last read configuration to compute changes for change listeners.
+func (c *ConfigReader) rememberConfig(parameters *cconfig.ConfigParams, config *cconfig.ConfigParams) {
+	c.changes.remember(parameters, config)
+}
+
+// changeListenerForwarder receives change notifications from underlying readers
+// and forwards them to the listeners of the target reader.
+type changeListenerForwarder struct {
+	target *ConfigReader
+}
+
+func (c *changeListenerForwarder) Notify(ctx context.Context, correlationId string, args *crun.Parameters) {
+	// Changes of underlying readers are replaced with changes of the target reader
+	if _, ok := ConfigChangeFromArgs(args); ok {
+		args = args.Clone()
+		args.Remove(ConfigChangeArgKey)
+	}
+	c.target.NotifyChangeListeners(ctx, correlationId, args)
 }
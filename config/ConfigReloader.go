@@ -0,0 +1,237 @@
+package config
+
+import (
+	"context"
+	"sync"
+
+	cconfig "github.com/pip-services3-gox/pip-services3-commons-gox/config"
+	crun "github.com/pip-services3-gox/pip-services3-commons-gox/run"
+)
+
+// ConfigReloader keeps the last read configuration of a config reader and re-reads it
+// when the reader notifies about changes. Its listeners are notified only when configuration
+// was actually changed, and notification arguments carry *ConfigChange under ConfigChangeArgKey
+// with added, removed and changed keys, so components can reconfigure only affected sections.
+//	see ConfigChange
+//	see ConfigChangeFromArgs
+//	Example:
+//		reloader := NewConfigReloader(NewYamlConfigReader("config.yml"), nil)
+//		reloader.AddChangeListener(ctx, listener)
+//		err := reloader.Open(ctx, "123")
+//		...
+//		func (c *MyListener) Notify(ctx context.Context, correlationId string, args *crun.Parameters) {
+//			if change, ok := ConfigChangeFromArgs(args); ok && change.HasSection("connection") {
+//				c.reconnect(change.NewConfig.GetSection("connection"))
+//			}
+//		}
+type ConfigReloader struct {
+	reader       IConfigReader
+	parameters   *cconfig.ConfigParams
+	config       *cconfig.ConfigParams
+	opened       bool
+	mtx          sync.Mutex
+	reloadMtx    sync.Mutex
+	listeners    []crun.INotifiable
+	listenersMtx sync.Mutex
+}
+
+// NewConfigReloader creates a new instance of the config reloader.
+//	Parameters:
+//		- reader IConfigReader a config reader to read configuration.
+//		- parameters *cconfig.ConfigParams values to parameters the configuration or nil.
+//	Returns: *ConfigReloader
+func NewConfigReloader(reader IConfigReader, parameters *cconfig.ConfigParams) *ConfigReloader {
+	return &ConfigReloader{
+		reader:     reader,
+		parameters: parameters,
+		listeners:  make([]crun.INotifiable, 0),
+	}
+}
+
+// Reader gets the underlying config reader.
+//	Returns: IConfigReader the config reader.
+func (c *ConfigReloader) Reader() IConfigReader {
+	return c.reader
+}
+
+// Config gets the last read configuration.
+//	Returns: *cconfig.ConfigParams the configuration or nil if it wasn't read yet.
+func (c *ConfigReloader) Config() *cconfig.ConfigParams {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	return c.config
+}
+
+// IsOpen checks if the component is opened.
+//	Returns: bool true if the component has been opened and false otherwise.
+func (c *ConfigReloader) IsOpen() bool {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	return c.opened
+}
+
+// Open starts listening to changes of the reader and reads the initial configuration.
+// The reloader subscribes before reading, so changes made meanwhile are not lost.
+//	Parameters:
+//		- ctx context.Context
+//		- correlationId string transaction id to trace execution through call chain.
+//	Returns: error or nil if no errors occurred.
+func (c *ConfigReloader) Open(ctx context.Context, correlationId string) error {
+	c.reloadMtx.Lock()
+	defer c.reloadMtx.Unlock()
+
+	if c.IsOpen() {
+		return nil
+	}
+
+	// Notifications wait for the initial read on the reload lock
+	c.reader.AddChangeListener(ctx, c)
+
+	config, err := c.reader.ReadConfig(ctx, correlationId, c.parameters)
+	if err != nil {
+		c.reader.RemoveChangeListener(ctx, c)
+		return err
+	}
+
+	c.mtx.Lock()
+	c.config = config
+	c.opened = true
+	c.mtx.Unlock()
+
+	return nil
+}
+
+// Close stops listening to changes of the reader.
+//	Parameters:
+//		- ctx context.Context
+//		- correlationId string transaction id to trace execution through call chain.
+//	Returns: error or nil if no errors occurred.
+func (c *ConfigReloader) Close(ctx context.Context, correlationId string) error {
+	c.mtx.Lock()
+	opened := c.opened
+	c.opened = false
+	c.mtx.Unlock()
+
+	if opened {
+		c.reader.RemoveChangeListener(ctx, c)
+	}
+	return nil
+}
+
+// Reload re-reads configuration and notifies listeners when it was changed.
+// When reading fails the last read configuration is kept.
+//	Parameters:
+//		- ctx context.Context
+//		- correlationId string transaction id to trace execution through call chain.
+//	Returns: *ConfigChange, error the differences with the previous configuration or error.
+func (c *ConfigReloader) Reload(ctx context.Context, correlationId string) (*ConfigChange, error) {
+	c.reloadMtx.Lock()
+	defer c.reloadMtx.Unlock()
+
+	return c.reload(ctx, correlationId, nil)
+}
+
+// reload reads configuration and notifies listeners. It must be called under the reload lock,
+// so concurrent reloads compute and deliver changes in the order they were read.
+// When the reader notification already carries *ConfigChange, its new configuration
+// is used instead of reading the configuration again.
+func (c *ConfigReloader) reload(ctx context.Context, correlationId string,
+	args *crun.Parameters) (*ConfigChange, error) {
+
+	var config *cconfig.ConfigParams
+	if readerChange, ok := ConfigChangeFromArgs(args); ok {
+		config = readerChange.NewConfig
+	} else {
+		var err error
+		config, err = c.reader.ReadConfig(ctx, correlationId, c.parameters)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	c.mtx.Lock()
+	change := DiffConfig(c.config, config)
+	c.config = config
+	c.mtx.Unlock()
+
+	if !change.IsEmpty() {
+		if args == nil {
+			args = crun.NewEmptyParameters()
+		} else {
+			args = args.Clone()
+		}
+		args.Put(ConfigChangeArgKey, change)
+		c.notifyChangeListeners(ctx, correlationId, args)
+	}
+	return change, nil
+}
+
+// Notify is called by the underlying reader when configuration was changed.
+// The configuration attached by the reader under ConfigChangeArgKey is reused,
+// and the change is recomputed against the last configuration of the reloader.
+//	Parameters:
+//		- ctx context.Context
+//		- correlationId string transaction id to trace execution through call chain.
+//		- args *crun.Parameters notification arguments.
+func (c *ConfigReloader) Notify(ctx context.Context, correlationId string, args *crun.Parameters) {
+	c.reloadMtx.Lock()
+	defer c.reloadMtx.Unlock()
+
+	// Notifications after a failed open or close are ignored
+	if !c.IsOpen() {
+		return
+	}
+
+	// Failed reads keep the last good configuration until the next change
+	_, _ = c.reload(ctx, correlationId, args)
+}
+
+// AddChangeListener adds a listener that will be notified when configuration is changed.
+//	Parameters:
+//		- ctx context.Context
+//		- listener crun.INotifiable a listener to be added.
+func (c *ConfigReloader) AddChangeListener(ctx context.Context, listener crun.INotifiable) {
+	if listener == nil {
+		return
+	}
+
+	c.listenersMtx.Lock()
+	defer c.listenersMtx.Unlock()
+
+	for _, item := range c.listeners {
+		if item == listener {
+			return
+		}
+	}
+	c.listeners = append(c.listeners, listener)
+}
+
+// RemoveChangeListener removes a previously added change listener.
+//	Parameters:
+//		- ctx context.Context
+//		- listener crun.INotifiable a listener to be removed.
+func (c *ConfigReloader) RemoveChangeListener(ctx context.Context, listener crun.INotifiable) {
+	c.listenersMtx.Lock()
+	defer c.listenersMtx.Unlock()
+
+	for index, item := range c.listeners {
+		if item == listener {
+			c.listeners = append(c.listeners[:index], c.listeners[index+1:]...)
+			break
+		}
+	}
+}
+
+// notifyChangeListeners notifies listeners outside of the lock, so they can use the reloader.
+func (c *ConfigReloader) notifyChangeListeners(ctx context.Context, correlationId string, args *crun.Parameters) {
+	c.listenersMtx.Lock()
+	listeners := make([]crun.INotifiable, len(c.listeners))
+	copy(listeners, c.listeners)
+	c.listenersMtx.Unlock()
+
+	for _, listener := range listeners {
+		listener.Notify(ctx, correlationId, args)
+	}
+}
@@ -0,0 +1,165 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	cconfig "github.com/pip-services3-gox/pip-services3-commons-gox/config"
+)
+
+// ConfigSource describes where a configuration value came from.
+type ConfigSource struct {
+	Reader    string `json:"reader"`    // A name of the reader, like "json", "yaml" or "env"
+	Path      string `json:"path"`      // A path to the file or a name of the environment variable
+	Line      int    `json:"line"`      // A line in the file starting from 1, or 0 when unknown
+	Parameter string `json:"parameter"` // A name of the template parameter that produced the value
+}
+
+// String converts the source into a human-readable string.
+//	Returns: string the source description.
+func (c *ConfigSource) String() string {
+	builder := strings.Builder{}
+	builder.WriteString(c.Reader)
+	if c.Path != "" {
+		builder.WriteString(" ")
+		builder.WriteString(c.Path)
+		if c.Line > 0 {
+			builder.WriteString(":")
+			builder.WriteString(strconv.Itoa(c.Line))
+		}
+	}
+	if c.Parameter != "" {
+		builder.WriteString(" {{")
+		builder.WriteString(c.Parameter)
+		builder.WriteString("}}")
+	}
+	return builder.String()
+}
+
+// ReadConfigWithSources reads configuration using the given reader and returns
+// sources of configuration values by their keys. When the reader doesn't implement
+// IConfigSourcesReader all values are attributed to the reader itself.
+//	Parameters:
+//		- ctx context.Context
+//		- correlationId string transaction id to trace execution through call chain.
+//		- reader IConfigReader a reader to read configuration.
+//		- parameters *cconfig.ConfigParams values to parameters the configuration.
+//	Returns: *cconfig.ConfigParams, map[string]*ConfigSource, error
+func ReadConfigWithSources(ctx context.Context, correlationId string, reader IConfigReader,
+	parameters *cconfig.ConfigParams) (*cconfig.ConfigParams, map[string]*ConfigSource, error) {
+
+	if sourcesReader, ok := reader.(IConfigSourcesReader); ok {
+		return sourcesReader.ReadConfigWithSources(ctx, correlationId, parameters)
+	}
+
+	config, err := reader.ReadConfig(ctx, correlationId, parameters)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sources := make(map[string]*ConfigSource)
+	name := fmt.Sprintf("%T", reader)
+	for _, key := range config.Keys() {
+		sources[key] = &ConfigSource{Reader: name}
+	}
+	return config, sources, nil
+}
+
+// configSourceFile holds content of a configuration file to locate keys in it.
+type configSourceFile struct {
+	path  string
+	lines []string
+}
+
+// locate finds the line where the configuration key is defined.
+// When partial is true, the key can be located by its trailing sections,
+// which is used for included files defined relatively to the include directive.
+func (c configSourceFile) locate(key string, partial bool) (int, bool) {
+	sections := make([]string, 0)
+	for _, section := range strings.Split(key, ".") {
+		if _, err := strconv.Atoi(section); err != nil && section != "" {
+			sections = append(sections, section)
+		}
+	}
+	if len(sections) == 0 {
+		return 0, false
+	}
+
+	for start := 0; start < len(sections); start++ {
+		if line, ok := c.find(sections[start:]); ok {
+			return line, true
+		}
+		if !partial {
+			break
+		}
+	}
+	return 0, false
+}
+
+// find sequentially searches key sections in the file lines.
+func (c configSourceFile) find(sections []string) (int, bool) {
+	line := 0
+	for _, section := range sections {
+		section = strings.ToLower(section)
+
+		found := -1
+		for i := line; i < len(c.lines); i++ {
+			trimmed := strings.TrimSpace(c.lines[i])
+			if strings.HasPrefix(trimmed, "#") || strings.HasPrefix(trimmed, "!") || strings.HasPrefix(trimmed, "//") {
+				continue
+			}
+			if containsConfigSection(strings.ToLower(c.lines[i]), section) {
+				found = i
+				break
+			}
+		}
+		if found < 0 {
+			return 0, false
+		}
+		line = found
+	}
+	return line, true
+}
+
+// containsConfigSection checks if the line defines the key section like `section:`, `"section" =`,
+// `[section]` or `section.subsection`. Both line and section shall be in lower case.
+func containsConfigSection(line string, section string) bool {
+	for start := 0; start < len(line); {
+		pos := strings.Index(line[start:], section)
+		if pos < 0 {
+			return false
+		}
+		pos += start
+		start = pos + 1
+
+		if pos > 0 && !strings.ContainsRune(" \t\"'{,[.", rune(line[pos-1])) {
+			continue
+		}
+
+		rest := line[pos+len(section):]
+		if strings.HasPrefix(rest, "\"") || strings.HasPrefix(rest, "'") {
+			rest = rest[1:]
+		}
+		if strings.HasPrefix(rest, ".") {
+			return true
+		}
+		rest = strings.TrimLeft(rest, " \t")
+		if strings.HasPrefix(rest, ":") || strings.HasPrefix(rest, "=") || strings.HasPrefix(rest, "]") {
+			return true
+		}
+	}
+	return false
+}
+
+var templateParameterPattern = regexp.MustCompile(`\{\{\{?\s*([A-Za-z_][A-Za-z0-9_.\-]*)`)
+
+// findTemplateParameter finds the first template parameter used in the text.
+func findTemplateParameter(text string) string {
+	if match := templateParameterPattern.FindStringSubmatch(text); match != nil {
+		return match[1]
+	}
+	return ""
+}
@@ -0,0 +1,136 @@
+package config
+
+import (
+	refl "reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	cconfig "github.com/pip-services3-gox/pip-services3-commons-gox/config"
+	"github.com/pip-services3-gox/pip-services3-commons-gox/convert"
+	"github.com/pip-services3-gox/pip-services3-commons-gox/errors"
+	"github.com/pip-services3-gox/pip-services3-commons-gox/validate"
+)
+
+// ValidateConfig validates configuration parameters against a validation schema.
+// Configuration is converted into a hierarchical object where numeric and boolean
+// values are converted into their natural types, and arrays are restored from indexed keys.
+// Since any configuration value is a text, values are always accepted by string types.
+//	Parameters:
+//		- correlationId string transaction id to trace execution through call chain.
+//		- config *cconfig.ConfigParams configuration parameters to be validated.
+//		- schema validate.ISchema a validation schema or nil to skip validation.
+//	Returns: error a ConfigError that lists every violation with its key path or nil if configuration is valid.
+func ValidateConfig(correlationId string, config *cconfig.ConfigParams, schema validate.ISchema) error {
+	if schema == nil || config == nil {
+		return nil
+	}
+
+	value := configToObject(config)
+	violations := make([]*validate.ValidationResult, 0)
+	for _, result := range schema.Validate(value) {
+		if result.Type() != validate.Error {
+			continue
+		}
+		if result.Code() == "TYPE_MISMATCH" && isStringType(result.Expected()) {
+			continue
+		}
+		violations = append(violations, result)
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+
+	messages := make([]string, len(violations))
+	for i, violation := range violations {
+		messages[i] = violation.Message()
+	}
+
+	return errors.NewConfigError(
+		correlationId,
+		"INVALID_CONFIG",
+		"Invalid configuration: "+strings.Join(messages, "; "),
+	).WithDetails("violations", violations)
+}
+
+// isStringType checks if expected type of validation result is a string type.
+func isStringType(typ any) bool {
+	switch t := typ.(type) {
+	case convert.TypeCode:
+		return t == convert.String
+	case refl.Type:
+		return t.Kind() == refl.String
+	case string:
+		return strings.EqualFold(t, "string")
+	}
+	return false
+}
+
+// configToObject converts flat configuration parameters into a hierarchical object.
+func configToObject(config *cconfig.ConfigParams) any {
+	result := make(map[string]any)
+
+	keys := config.Keys()
+	sort.Strings(keys)
+	for _, key := range keys {
+		value, _ := config.GetAsNullableString(key)
+		names := strings.Split(key, ".")
+
+		node := result
+		for i, name := range names {
+			if i == len(names)-1 {
+				node[name] = configValueToObject(value)
+				break
+			}
+			child, ok := node[name].(map[string]any)
+			if !ok {
+				child = make(map[string]any)
+				node[name] = child
+			}
+			node = child
+		}
+	}
+
+	return restoreConfigArrays(result)
+}
+
+// restoreConfigArrays converts maps with sequential numeric keys into arrays.
+func restoreConfigArrays(value any) any {
+	m, ok := value.(map[string]any)
+	if !ok {
+		return value
+	}
+
+	for key, item := range m {
+		m[key] = restoreConfigArrays(item)
+	}
+
+	if len(m) == 0 {
+		return m
+	}
+
+	array := make([]any, len(m))
+	for key, item := range m {
+		index, err := strconv.Atoi(key)
+		if err != nil || index < 0 || index >= len(m) || strconv.Itoa(index) != key {
+			return m
+		}
+		array[index] = item
+	}
+	return array
+}
+
+// configValueToObject converts configuration value into its natural type.
+func configValueToObject(value string) any {
+	if value == "true" || value == "false" {
+		return value == "true"
+	}
+	if i, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return i
+	}
+	if f, err := strconv.ParseFloat(value, 64); err == nil {
+		return f
+	}
+	return value
+}
@@ -10,6 +10,12 @@ import (
 var MemoryConfigReaderDescriptor = refer.NewDescriptor("pip-services", "config-reader", "memory", "*", "1.0")
 var JsonConfigReaderDescriptor = refer.NewDescriptor("pip-services", "config-reader", "json", "*", "1.0")
 var YamlConfigReaderDescriptor = refer.NewDescriptor("pip-services", "config-reader", "yaml", "*", "1.0")
+var TomlConfigReaderDescriptor = refer.NewDescriptor("pip-services", "config-reader", "toml", "*", "1.0")
+var PropertiesConfigReaderDescriptor = refer.NewDescriptor("pip-services", "config-reader", "properties", "*", "1.0")
+var FileConfigReaderDescriptor = refer.NewDescriptor("pip-services", "config-reader", "file", "*", "1.0")
+var EnvConfigReaderDescriptor = refer.NewDescriptor("pip-services", "config-reader", "env", "*", "1.0")
+var CompositeConfigReaderDescriptor = refer.NewDescriptor("pip-services", "config-reader", "composite", "*", "1.0")
+var HttpConfigReaderDescriptor = refer.NewDescriptor("pip-services", "config-reader", "http", "*", "1.0")
 
 // NewDefaultConfigReaderFactory create a new instance of the factory.
 //	Returns: *build.Factory
@@ -19,6 +25,12 @@ func NewDefaultConfigReaderFactory() *build.Factory {
 	factory.RegisterType(MemoryConfigReaderDescriptor, NewEmptyMemoryConfigReader)
 	factory.RegisterType(JsonConfigReaderDescriptor, NewEmptyJsonConfigReader)
 	factory.RegisterType(YamlConfigReaderDescriptor, NewEmptyYamlConfigReader)
+	factory.RegisterType(TomlConfigReaderDescriptor, NewEmptyTomlConfigReader)
+	factory.RegisterType(PropertiesConfigReaderDescriptor, NewEmptyPropertiesConfigReader)
+	factory.RegisterType(FileConfigReaderDescriptor, NewEmptyAutoFileConfigReader)
+	factory.RegisterType(EnvConfigReaderDescriptor, NewEmptyEnvConfigReader)
+	factory.RegisterType(CompositeConfigReaderDescriptor, NewEmptyCompositeConfigReader)
+	factory.RegisterType(HttpConfigReaderDescriptor, NewEmptyHttpConfigReader)
 
 	return factory
 }
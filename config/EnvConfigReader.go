@@ -0,0 +1,216 @@
+package config
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	cconfig "github.com/pip-services3-gox/pip-services3-commons-gox/config"
+	"github.com/pip-services3-gox/pip-services3-commons-gox/errors"
+)
+
+// EnvConfigReader is a config reader that reads configuration from environment variables.
+// Variable names are stripped of the prefix, split by the separator and converted
+// into lower-case configuration sections. For instance, with "APP" prefix and
+// default "__" separator APP__CONNECTION__HOST becomes connection.host.
+// A prefix is required, so unrelated variables like PATH or HOME don't get into configuration.
+// Reading of all variables without a prefix must be enabled explicitly with read_all option.
+// Variable values are parameterized using Mustache template engine implemented in expressions module.
+//	Configuration parameters:
+//		- prefix: prefix of environment variables to be read
+//		- separator: separator of configuration sections in variable names (default: "__")
+//		- options:
+//			- read_all: true to read all variables when prefix is not set (default: false)
+//		- parameters: this entire section is used as template parameters
+//	see IConfigReader
+//	Example:
+//		APP__CONNECTION__HOST=10.1.1.100
+//		APP__CONNECTION__PORT=8080
+//
+//		configReader := NewEnvConfigReader("APP")
+//		res, err := configReader.ReadConfig(context.Background(), "123", nil)
+//			// Result: connection.host=10.1.1.100;connection.port=8080
+type EnvConfigReader struct {
+	*ConfigReader
+	prefix    string
+	separator string
+	readAll   bool
+}
+
+const (
+	// EnvConfigReaderPrefixKey is a constant for prefix key
+	EnvConfigReaderPrefixKey = "prefix"
+	// EnvConfigReaderSeparatorKey is a constant for separator key
+	EnvConfigReaderSeparatorKey = "separator"
+	// EnvConfigReaderReadAllKey is a constant for read all option key
+	EnvConfigReaderReadAllKey = "options.read_all"
+	// EnvConfigReaderDefaultSeparator is a default separator of configuration sections
+	EnvConfigReaderDefaultSeparator = "__"
+)
+
+// NewEmptyEnvConfigReader creates a new instance of the config reader.
+//	Returns: *EnvConfigReader
+func NewEmptyEnvConfigReader() *EnvConfigReader {
+	return &EnvConfigReader{
+		ConfigReader: NewConfigReader(),
+		separator:    EnvConfigReaderDefaultSeparator,
+	}
+}
+
+// NewEnvConfigReader creates a new instance of the config reader.
+//	Parameters: prefix string a prefix of environment variables to be read.
+//	Returns: *EnvConfigReader
+func NewEnvConfigReader(prefix string) *EnvConfigReader {
+	return &EnvConfigReader{
+		ConfigReader: NewConfigReader(),
+		prefix:       prefix,
+		separator:    EnvConfigReaderDefaultSeparator,
+	}
+}
+
+// Configure component by passing configuration parameters.
+//	Parameters:
+//		- ctx context.Context
+//		- config *cconfig.ConfigParams configuration parameters to be set.
+func (c *EnvConfigReader) Configure(ctx context.Context, config *cconfig.ConfigParams) {
+	c.ConfigReader.Configure(ctx, config)
+	c.prefix = config.GetAsStringWithDefault(EnvConfigReaderPrefixKey, c.prefix)
+	c.separator = config.GetAsStringWithDefault(EnvConfigReaderSeparatorKey, c.separator)
+	c.readAll = config.GetAsBooleanWithDefault(EnvConfigReaderReadAllKey, c.readAll)
+}
+
+// Prefix gets the prefix of environment variables.
+//	Returns: string the prefix of environment variables.
+func (c *EnvConfigReader) Prefix() string {
+	return c.prefix
+}
+
+// SetPrefix sets the prefix of environment variables.
+//	Parameters:
+//		- prefix string a new prefix of environment variables.
+func (c *EnvConfigReader) SetPrefix(prefix string) {
+	c.prefix = prefix
+}
+
+// ReadAll checks if all environment variables are read when prefix is not set.
+//	Returns: bool true if all variables are read without prefix.
+func (c *EnvConfigReader) ReadAll() bool {
+	return c.readAll
+}
+
+// SetReadAll enables reading of all environment variables when prefix is not set.
+// Use it with care, since the whole process environment including secrets gets into configuration.
+//	Parameters:
+//		- readAll bool true to read all variables without prefix.
+func (c *EnvConfigReader) SetReadAll(readAll bool) {
+	c.readAll = readAll
+}
+
+// ReadConfig reads configuration from environment variables, parameterize
+// it with given values and returns a new ConfigParams object.
+//	Parameters:
+//		- ctx context.Context
+//		- correlationId string transaction id to trace execution through call chain.
+//		- parameters *cconfig.ConfigParams values to parameters the configuration.
+//	Returns: *cconfig.ConfigParams, error
+func (c *EnvConfigReader) ReadConfig(ctx context.Context, correlationId string,
+	parameters *cconfig.ConfigParams) (*cconfig.ConfigParams, error) {
+
+	config, _, err := c.ReadConfigWithSources(ctx, correlationId, parameters)
+	return config, err
+}
+
+// ReadConfigWithSources reads configuration from environment variables, parameterize it with given values
+// and returns sources of configuration values with names of environment variables they came from.
+//	Parameters:
+//		- ctx context.Context
+//		- correlationId string transaction id to trace execution through call chain.
+//		- parameters *cconfig.ConfigParams values to parameters the configuration.
+//	Returns: *cconfig.ConfigParams, map[string]*ConfigSource, error configuration, its sources
+//	or error if prefix is not set and reading of all variables is not enabled.
+func (c *EnvConfigReader) ReadConfigWithSources(ctx context.Context, correlationId string,
+	parameters *cconfig.ConfigParams) (*cconfig.ConfigParams, map[string]*ConfigSource, error) {
+
+	if c.prefix == "" && !c.readAll {
+		return nil, nil, errors.NewConfigError(
+			correlationId, "NO_PREFIX", "Environment variables prefix is not set",
+		).WithDetails("option", EnvConfigReaderReadAllKey)
+	}
+
+	separator := c.separator
+	if separator == "" {
+		separator = EnvConfigReaderDefaultSeparator
+	}
+
+	prefix := ""
+	if c.prefix != "" {
+		prefix = strings.ToUpper(c.prefix) + separator
+	}
+
+	result := cconfig.NewEmptyConfigParams()
+	sources := make(map[string]*ConfigSource)
+	for _, variable := range os.Environ() {
+		pos := strings.Index(variable, "=")
+		if pos <= 0 {
+			continue
+		}
+		name := variable[:pos]
+		value := variable[pos+1:]
+
+		if prefix != "" {
+			if !strings.HasPrefix(strings.ToUpper(name), prefix) {
+				continue
+			}
+			name = name[len(prefix):]
+		}
+
+		key := c.composeKey(name, separator)
+		if key == "" {
+			continue
+		}
+
+		source := &ConfigSource{Reader: "env", Path: variable[:pos]}
+		if strings.Contains(value, "{{") {
+			source.Parameter = findTemplateParameter(value)
+
+			var err error
+			value, err = c.Parameterize(value, parameters)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+		result.Put(key, value)
+		sources[key] = source
+	}
+
+	if err := c.ValidateConfig(correlationId, result); err != nil {
+		return nil, nil, err
+	}
+	return result, sources, nil
+}
+
+// composeKey converts variable name into a configuration key.
+func (c *EnvConfigReader) composeKey(name string, separator string) string {
+	sections := make([]string, 0)
+	for _, section := range strings.Split(name, separator) {
+		if section != "" {
+			sections = append(sections, strings.ToLower(section))
+		}
+	}
+	return strings.Join(sections, ".")
+}
+
+// ReadEnvConfig reads configuration from environment variables with the given prefix,
+// parameterize it with given values and returns a new ConfigParams object.
+//	Parameters:
+//		- ctx context.Context
+//		- correlationId string transaction id to trace execution through call chain.
+//		- prefix string a prefix of environment variables to be read.
+//		- parameters *cconfig.ConfigParams values to parameters the configuration.
+//	Returns: *cconfig.ConfigParams, error
+func ReadEnvConfig(ctx context.Context, correlationId string, prefix string,
+	parameters *cconfig.ConfigParams) (*cconfig.ConfigParams, error) {
+
+	reader := NewEnvConfigReader(prefix)
+	return reader.ReadConfig(ctx, correlationId, parameters)
+}
@@ -0,0 +1,40 @@
+package config
+
+import (
+	"context"
+	"os"
+
+	"github.com/pip-services3-gox/pip-services3-commons-gox/errors"
+)
+
+// EnvSecretResolver is a secret resolver that reads secrets from environment variables.
+//	see ISecretResolver
+//	Example:
+//		connection:
+//			password: ${env:DB_PASS}
+type EnvSecretResolver struct {
+}
+
+// NewEnvSecretResolver creates a new instance of the secret resolver.
+//	Returns: *EnvSecretResolver
+func NewEnvSecretResolver() *EnvSecretResolver {
+	return &EnvSecretResolver{}
+}
+
+// ResolveSecret resolves a secret from environment variable.
+//	Parameters:
+//		- ctx context.Context
+//		- correlationId string transaction id to trace execution through call chain.
+//		- reference string a name of environment variable.
+//	Returns: string, error the secret value and error.
+func (c *EnvSecretResolver) ResolveSecret(ctx context.Context, correlationId string, reference string) (string, error) {
+	value, ok := os.LookupEnv(reference)
+	if !ok {
+		return "", errors.NewConfigError(
+			correlationId,
+			"SECRET_NOT_FOUND",
+			"Environment variable "+reference+" is not defined",
+		).WithDetails("reference", reference)
+	}
+	return value, nil
+}
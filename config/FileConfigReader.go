@@ -2,28 +2,60 @@ package config
 
 import (
 	"context"
+	"crypto/sha256"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
 
 	cconfig "github.com/pip-services3-gox/pip-services3-commons-gox/config"
+	"github.com/pip-services3-gox/pip-services3-commons-gox/errors"
+	crun "github.com/pip-services3-gox/pip-services3-commons-gox/run"
 )
 
+// ConfigIncludeExpander is a function that finds include directives in the configuration
+// content and replaces them with the content returned by the include function.
+// Each format defines its own directive syntax, like "$include" in JSON or "!include" in YAML.
+type ConfigIncludeExpander func(content string, include func(path string) (string, error)) (string, error)
+
 // FileConfigReader is an abstract config reader that reads configuration from a file.
 // Child classes add support for config files in their specific format like JSON, YAML or property files.
+// When change listeners are registered the reader periodically checks the file
+// modification time and content hash, and notifies the listeners when the file changes.
+// Files included by the last read are checked as well.
+// Configuration values may contain secret references like ${secret:file:/run/secrets/db}
+// or ${env:DB_PASS} that are resolved when configuration is read. The "env" and "file"
+// resolvers are registered by default and more can be added using RegisterSecretResolver.
 //	Configuration parameters:
 //		- path: path to configuration file
+//		- options:
+//			- check_interval: interval in milliseconds to check the file for changes (default: 1000)
 //		- parameters: this entire section is used as template parameters
 type FileConfigReader struct {
 	*ConfigReader
-	path string
+	path          string
+	checkInterval int64
+	mtx           sync.Mutex
+	stopWatch     chan struct{}
+	files         []string
+	resolvers     map[string]ISecretResolver
 }
 
 // FileConfigReaderPathKey is a constant for path key
 const FileConfigReaderPathKey = "path"
 
+// FileConfigReaderCheckIntervalKey is a constant for check interval key
+const FileConfigReaderCheckIntervalKey = "options.check_interval"
+
 // NewEmptyFileConfigReader creates a new instance of the config reader.
 //	Returns: *FileConfigReader
 func NewEmptyFileConfigReader() *FileConfigReader {
 	return &FileConfigReader{
-		ConfigReader: NewConfigReader(),
+		ConfigReader:  NewConfigReader(),
+		checkInterval: 1000,
+		resolvers:     newDefaultSecretResolvers(),
 	}
 }
 
@@ -33,8 +65,10 @@ func NewEmptyFileConfigReader() *FileConfigReader {
 //	Returns: *FileConfigReader
 func NewFileConfigReader(path string) *FileConfigReader {
 	return &FileConfigReader{
-		ConfigReader: NewConfigReader(),
-		path:         path,
+		ConfigReader:  NewConfigReader(),
+		path:          path,
+		checkInterval: 1000,
+		resolvers:     newDefaultSecretResolvers(),
 	}
 }
 
@@ -44,12 +78,20 @@ func NewFileConfigReader(path string) *FileConfigReader {
 //		- config *cconfig.ConfigParams configuration parameters to be set.
 func (c *FileConfigReader) Configure(ctx context.Context, config *cconfig.ConfigParams) {
 	c.ConfigReader.Configure(ctx, config)
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
 	c.path = config.GetAsStringWithDefault(FileConfigReaderPathKey, c.path)
+	c.checkInterval = config.GetAsLongWithDefault(FileConfigReaderCheckIntervalKey, c.checkInterval)
 }
 
 // Path get the path to configuration file..
 //	Returns: string the path to configuration file.
 func (c *FileConfigReader) Path() string {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
 	return c.path
 }
 
@@ -57,5 +99,399 @@ func (c *FileConfigReader) Path() string {
 //	Parameters:
 //		- path string a new path to configuration file.
 func (c *FileConfigReader) SetPath(path string) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
 	c.path = path
 }
+
+// CheckInterval gets the interval to check the configuration file for changes.
+//	Returns: int64 the interval in milliseconds.
+func (c *FileConfigReader) CheckInterval() int64 {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	return c.checkInterval
+}
+
+// SetCheckInterval sets the interval to check the configuration file for changes.
+// The new interval is applied the next time watching is started.
+//	Parameters:
+//		- interval int64 a new interval in milliseconds.
+func (c *FileConfigReader) SetCheckInterval(interval int64) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.checkInterval = interval
+}
+
+// RegisterSecretResolver registers a resolver for secret references with the given name.
+// A registered resolver replaces a previous one with the same name.
+//	Parameters:
+//		- name string a resolver name used in references, like "env" in ${env:DB_PASS}.
+//		- resolver ISecretResolver a secret resolver or nil to remove the resolver.
+func (c *FileConfigReader) RegisterSecretResolver(name string, resolver ISecretResolver) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if c.resolvers == nil {
+		c.resolvers = newDefaultSecretResolvers()
+	}
+	if resolver == nil {
+		delete(c.resolvers, name)
+	} else {
+		c.resolvers[name] = resolver
+	}
+}
+
+// ResolveSecrets replaces secret references in configuration values with resolved secrets.
+// References prefixed with "secret:" must be resolved, otherwise an error is returned.
+// Other references are resolved only when their resolver is registered and kept as is otherwise.
+//	Parameters:
+//		- ctx context.Context
+//		- correlationId string transaction id to trace execution through call chain.
+//		- config *cconfig.ConfigParams configuration with secret references to be resolved.
+//	Returns: error
+func (c *FileConfigReader) ResolveSecrets(ctx context.Context, correlationId string, config *cconfig.ConfigParams) error {
+	if config == nil {
+		return nil
+	}
+
+	c.mtx.Lock()
+	resolvers := make(map[string]ISecretResolver, len(c.resolvers))
+	for name, resolver := range c.resolvers {
+		resolvers[name] = resolver
+	}
+	c.mtx.Unlock()
+
+	for _, key := range config.Keys() {
+		value := config.GetAsString(key)
+		if !strings.Contains(value, "${") {
+			continue
+		}
+
+		var err error
+		value = secretReferencePattern.ReplaceAllStringFunc(value, func(match string) string {
+			if err != nil {
+				return match
+			}
+
+			reference := match[2 : len(match)-1]
+			required := strings.HasPrefix(reference, "secret:")
+			if required {
+				reference = reference[len("secret:"):]
+			}
+
+			name, reference, _ := strings.Cut(reference, ":")
+			resolver, ok := resolvers[name]
+			if !ok {
+				if required {
+					err = errors.NewConfigError(
+						correlationId,
+						"UNKNOWN_SECRET_RESOLVER",
+						"Secret resolver "+name+" is not registered",
+					).WithDetails("key", key)
+				}
+				return match
+			}
+
+			var secret string
+			secret, err = resolver.ResolveSecret(ctx, correlationId, reference)
+			return secret
+		})
+		if err != nil {
+			return err
+		}
+
+		config.Put(key, value)
+	}
+
+	return nil
+}
+
+var secretReferencePattern = regexp.MustCompile(`\$\{[A-Za-z0-9_\-]+:[^}]*\}`)
+
+func newDefaultSecretResolvers() map[string]ISecretResolver {
+	return map[string]ISecretResolver{
+		"env":  NewEnvSecretResolver(),
+		"file": NewFileSecretResolver(),
+	}
+}
+
+// ReadContent reads the configuration file and expands include directives in its content.
+// Included paths are resolved relative to the directory of the including file.
+// Included files are expanded recursively and cyclic includes are reported as errors.
+// Include directives are expanded before the content is parameterized.
+//	Parameters:
+//		- correlationId string transaction id to trace execution through call chain.
+//		- expander ConfigIncludeExpander a function to expand include directives or nil to skip them.
+//	Returns: string, error the configuration content and error.
+func (c *FileConfigReader) ReadContent(correlationId string, expander ConfigIncludeExpander) (string, error) {
+	path := c.Path()
+	if path == "" {
+		return "", errors.NewConfigError(correlationId, "NO_PATH", "Missing config file path")
+	}
+
+	// Included files are remembered to watch them for changes together with the main file
+	files := make([]string, 0)
+	content, err := c.readContent(correlationId, path, expander, []string{}, func(path string, content string) {
+		files = append(files, path)
+	})
+
+	c.mtx.Lock()
+	c.files = files
+	c.mtx.Unlock()
+
+	return content, err
+}
+
+func (c *FileConfigReader) readContent(correlationId string, path string,
+	expander ConfigIncludeExpander, stack []string, visit func(path string, content string)) (string, error) {
+
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		absPath = path
+	}
+
+	for _, p := range stack {
+		if p == absPath {
+			return "", errors.NewConfigError(
+				correlationId,
+				"INCLUDE_CYCLE",
+				"Cyclic include of configuration "+path+": "+strings.Join(append(stack, absPath), " -> "),
+			).WithDetails("path", path)
+		}
+	}
+
+	b, err := os.ReadFile(path)
+	if err != nil {
+		err = errors.NewFileError(
+			correlationId,
+			"READ_FAILED",
+			"Failed reading configuration "+path+": "+err.Error(),
+		).
+			WithDetails("path", path).WithCause(err)
+		return "", err
+	}
+
+	content := string(b)
+	if visit != nil {
+		visit(path, content)
+	}
+	if expander == nil {
+		return content, nil
+	}
+
+	stack = append(stack, absPath)
+	return expander(content, func(include string) (string, error) {
+		if !filepath.IsAbs(include) {
+			include = filepath.Join(filepath.Dir(path), include)
+		}
+		return c.readContent(correlationId, include, expander, stack, visit)
+	})
+}
+
+// LocateSources finds sources of configuration values read from the file and its includes.
+// Location of keys in files is best effort: the line is found by searching key sections
+// in the file content, and the template parameter is taken from that line.
+//	Parameters:
+//		- correlationId string transaction id to trace execution through call chain.
+//		- reader string a name of the reader format, like "json" or "yaml".
+//		- expander ConfigIncludeExpander a function to expand include directives or nil to skip them.
+//		- config *cconfig.ConfigParams configuration read from the file.
+//	Returns: map[string]*ConfigSource, error sources of configuration values by their keys and error.
+func (c *FileConfigReader) LocateSources(correlationId string, reader string,
+	expander ConfigIncludeExpander, config *cconfig.ConfigParams) (map[string]*ConfigSource, error) {
+
+	path := c.Path()
+	if path == "" {
+		return nil, errors.NewConfigError(correlationId, "NO_PATH", "Missing config file path")
+	}
+
+	files := make([]configSourceFile, 0)
+	_, err := c.readContent(correlationId, path, expander, []string{}, func(path string, content string) {
+		files = append(files, configSourceFile{path: path, lines: strings.Split(content, "\n")})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]*ConfigSource)
+	for _, key := range config.Keys() {
+		source := &ConfigSource{Reader: reader, Path: path}
+		for i, file := range files {
+			if line, ok := file.locate(key, i > 0); ok {
+				source.Path = file.path
+				source.Line = line + 1
+				source.Parameter = findTemplateParameter(file.lines[line])
+				break
+			}
+		}
+		result[key] = source
+	}
+	return result, nil
+}
+
+// AddChangeListener adds a listener that will be notified when configuration file is changed.
+// Watching of the file starts when the first listener is added.
+//	Parameters:
+//		- ctx context.Context
+//		- listener crun.INotifiable a listener to be added.
+func (c *FileConfigReader) AddChangeListener(ctx context.Context, listener crun.INotifiable) {
+	c.ConfigReader.AddChangeListener(ctx, listener)
+	if c.ChangeListenersCount() > 0 {
+		c.startWatching()
+	}
+}
+
+// RemoveChangeListener removes a previously added change listener.
+// Watching of the file stops when the last listener is removed.
+//	Parameters:
+//		- ctx context.Context
+//		- listener crun.INotifiable a listener to be removed.
+func (c *FileConfigReader) RemoveChangeListener(ctx context.Context, listener crun.INotifiable) {
+	c.ConfigReader.RemoveChangeListener(ctx, listener)
+	if c.ChangeListenersCount() == 0 {
+		c.stopWatching()
+	}
+}
+
+func (c *FileConfigReader) startWatching() {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if c.stopWatch != nil {
+		return
+	}
+
+	interval := c.checkInterval
+	if interval <= 0 {
+		interval = 1000
+	}
+
+	stop := make(chan struct{})
+	c.stopWatch = stop
+	last := make(map[string]fileFingerprint)
+	for _, path := range c.watchedFiles() {
+		if fingerprint := readFileFingerprint(path, fileFingerprint{}); fingerprint.exists {
+			last[path] = fingerprint
+		}
+	}
+
+	go func() {
+		ticker := time.NewTicker(time.Duration(interval) * time.Millisecond)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				c.mtx.Lock()
+				files := c.watchedFiles()
+				c.mtx.Unlock()
+
+				var changed string
+				last, changed = checkFileFingerprints(files, last)
+				if changed != "" {
+					c.NotifyChangeListeners(context.Background(), "",
+						crun.NewParametersFromTuples("path", changed))
+				}
+			}
+		}
+	}()
+}
+
+// watchedFiles gets the configuration file and files included into it by the last read.
+// It must be called under the lock.
+func (c *FileConfigReader) watchedFiles() []string {
+	files := []string{c.path}
+	for _, path := range c.files {
+		if path != c.path {
+			files = append(files, path)
+		}
+	}
+	return files
+}
+
+// checkFileFingerprints compares watched files with their previous states.
+// The first file is the main configuration file, and it is reported as changed when it appears
+// for the first time. Other files seen for the first time are recorded without notification
+// since their appearance is caused by a change of the including file.
+// Missing files keep their previous state until they are replaced.
+// Returns the new states and the path of a changed file or empty string if nothing was changed.
+func checkFileFingerprints(files []string,
+	last map[string]fileFingerprint) (map[string]fileFingerprint, string) {
+
+	changed := ""
+	current := make(map[string]fileFingerprint, len(files))
+	for index, path := range files {
+		previous, ok := last[path]
+		fingerprint := readFileFingerprint(path, previous)
+		if !fingerprint.exists {
+			if ok {
+				current[path] = previous
+			}
+			continue
+		}
+
+		current[path] = fingerprint
+		if changed == "" && ((!ok && index == 0) || (ok && !fingerprint.equals(previous))) {
+			changed = path
+		}
+	}
+	return current, changed
+}
+
+func (c *FileConfigReader) stopWatching() {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if c.stopWatch != nil {
+		close(c.stopWatch)
+		c.stopWatch = nil
+	}
+}
+
+// fileFingerprint captures the state of a file to detect its changes.
+type fileFingerprint struct {
+	exists  bool
+	path    string
+	modTime time.Time
+	size    int64
+	hash    [sha256.Size]byte
+}
+
+// readFileFingerprint reads the file state. The content hash is recalculated
+// only when the modification time or size differ from the previous state.
+func readFileFingerprint(path string, previous fileFingerprint) fileFingerprint {
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return fileFingerprint{path: path}
+	}
+
+	result := fileFingerprint{
+		exists:  true,
+		path:    path,
+		modTime: info.ModTime(),
+		size:    info.Size(),
+	}
+
+	if previous.exists && previous.path == path &&
+		previous.modTime.Equal(result.modTime) && previous.size == result.size {
+		result.hash = previous.hash
+	} else if b, err := os.ReadFile(path); err == nil {
+		result.hash = sha256.Sum256(b)
+	}
+
+	return result
+}
+
+// equals checks if the file content is the same.
+// Files touched without changing their content are considered equal.
+func (f fileFingerprint) equals(other fileFingerprint) bool {
+	if f.exists != other.exists || f.path != other.path {
+		return false
+	}
+	return f.hash == other.hash
+}
@@ -0,0 +1,43 @@
+package config
+
+import (
+	"context"
+	"os"
+	"strings"
+
+	"github.com/pip-services3-gox/pip-services3-commons-gox/errors"
+)
+
+// FileSecretResolver is a secret resolver that reads secrets from files,
+// like Docker or Kubernetes secrets mounted into /run/secrets.
+// Trailing line breaks are removed from the file content.
+//	see ISecretResolver
+//	Example:
+//		connection:
+//			password: ${secret:file:/run/secrets/db}
+type FileSecretResolver struct {
+}
+
+// NewFileSecretResolver creates a new instance of the secret resolver.
+//	Returns: *FileSecretResolver
+func NewFileSecretResolver() *FileSecretResolver {
+	return &FileSecretResolver{}
+}
+
+// ResolveSecret resolves a secret from a file.
+//	Parameters:
+//		- ctx context.Context
+//		- correlationId string transaction id to trace execution through call chain.
+//		- reference string a path to the secret file.
+//	Returns: string, error the secret value and error.
+func (c *FileSecretResolver) ResolveSecret(ctx context.Context, correlationId string, reference string) (string, error) {
+	b, err := os.ReadFile(reference)
+	if err != nil {
+		return "", errors.NewFileError(
+			correlationId,
+			"READ_FAILED",
+			"Failed reading secret "+reference+": "+err.Error(),
+		).WithDetails("path", reference).WithCause(err)
+	}
+	return strings.TrimRight(string(b), "\r\n"), nil
+}
@@ -0,0 +1,538 @@
+package config
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	cconfig "github.com/pip-services3-gox/pip-services3-commons-gox/config"
+	"github.com/pip-services3-gox/pip-services3-commons-gox/errors"
+	crun "github.com/pip-services3-gox/pip-services3-commons-gox/run"
+	"gopkg.in/yaml.v2"
+)
+
+// HttpConfigReader is a config reader that fetches configuration in JSON or YAML format from a URL.
+// Repeated requests use ETag to skip unchanged content. The last fetched content is kept in memory,
+// and the last content that was successfully parsed is kept on disk when a cache path is set,
+// so configuration can still be read when the remote server is unavailable, including after restart.
+// When fetched content cannot be parameterized or parsed, the last good content is used instead.
+// When change listeners are registered the reader periodically polls the URL
+// and notifies the listeners when the content changes.
+// The reader supports parameterization using Mustache template engine implemented in expressions module.
+//	Configuration parameters:
+//		- url: URL to fetch configuration from
+//		- format: configuration format "json" or "yaml" (default: detected by content type, URL extension or content)
+//		- options:
+//			- cache_path: path to a file to cache the last fetched configuration (default: no disk cache)
+//			- check_interval: interval in milliseconds to poll the URL for changes (default: 10000)
+//			- timeout: request timeout in milliseconds (default: 10000)
+//		- parameters: this entire section is used as template parameters
+//	see IConfigReader
+//	Example:
+//		configReader := NewHttpConfigReader("http://config-server/services/myservice.yml")
+//		configReader.SetCachePath("./cache/myservice.yml")
+//		res, err := configReader.ReadConfig(context.Background(), "123", nil)
+type HttpConfigReader struct {
+	*ConfigReader
+	url           string
+	format        string
+	cachePath     string
+	checkInterval int64
+	timeout       int64
+	client        *http.Client
+	content       string
+	contentType   string
+	etag          string
+	good          string
+	goodType      string
+	mtx           sync.Mutex
+	stopWatch     chan struct{}
+}
+
+const (
+	// HttpConfigReaderUrlKey is a constant for url key
+	HttpConfigReaderUrlKey = "url"
+	// HttpConfigReaderFormatKey is a constant for format key
+	HttpConfigReaderFormatKey = "format"
+	// HttpConfigReaderCachePathKey is a constant for cache path key
+	HttpConfigReaderCachePathKey = "options.cache_path"
+	// HttpConfigReaderCheckIntervalKey is a constant for check interval key
+	HttpConfigReaderCheckIntervalKey = "options.check_interval"
+	// HttpConfigReaderTimeoutKey is a constant for timeout key
+	HttpConfigReaderTimeoutKey = "options.timeout"
+)
+
+// NewEmptyHttpConfigReader creates a new instance of the config reader.
+//	Returns: *HttpConfigReader
+func NewEmptyHttpConfigReader() *HttpConfigReader {
+	return NewHttpConfigReader("")
+}
+
+// NewHttpConfigReader creates a new instance of the config reader.
+//	Parameters: url string a URL to fetch configuration from.
+//	Returns: *HttpConfigReader
+func NewHttpConfigReader(url string) *HttpConfigReader {
+	c := &HttpConfigReader{
+		ConfigReader:  NewConfigReader(),
+		url:           url,
+		checkInterval: 10000,
+		timeout:       10000,
+	}
+	c.trackChanges(c)
+	return c
+}
+
+// Configure component by passing configuration parameters.
+//	Parameters:
+//		- ctx context.Context
+//		- config *cconfig.ConfigParams configuration parameters to be set.
+func (c *HttpConfigReader) Configure(ctx context.Context, config *cconfig.ConfigParams) {
+	c.ConfigReader.Configure(ctx, config)
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.url = config.GetAsStringWithDefault(HttpConfigReaderUrlKey, c.url)
+	c.format = config.GetAsStringWithDefault(HttpConfigReaderFormatKey, c.format)
+	c.cachePath = config.GetAsStringWithDefault(HttpConfigReaderCachePathKey, c.cachePath)
+	c.checkInterval = config.GetAsLongWithDefault(HttpConfigReaderCheckIntervalKey, c.checkInterval)
+	c.timeout = config.GetAsLongWithDefault(HttpConfigReaderTimeoutKey, c.timeout)
+}
+
+// Url gets the URL to fetch configuration from.
+//	Returns: string the configuration URL.
+func (c *HttpConfigReader) Url() string {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	return c.url
+}
+
+// SetUrl sets the URL to fetch configuration from.
+//	Parameters:
+//		- url string a new configuration URL.
+func (c *HttpConfigReader) SetUrl(url string) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if c.url != url {
+		c.url = url
+		c.etag = ""
+		c.content = ""
+		c.contentType = ""
+		c.good = ""
+		c.goodType = ""
+	}
+}
+
+// CachePath gets the path to a file where the last fetched configuration is cached.
+//	Returns: string the cache file path or empty string when disk cache is disabled.
+func (c *HttpConfigReader) CachePath() string {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	return c.cachePath
+}
+
+// SetCachePath sets the path to a file where the last fetched configuration is cached.
+//	Parameters:
+//		- path string a cache file path or empty string to disable disk cache.
+func (c *HttpConfigReader) SetCachePath(path string) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.cachePath = path
+}
+
+// CheckInterval gets the interval to poll the URL for changes.
+//	Returns: int64 the interval in milliseconds.
+func (c *HttpConfigReader) CheckInterval() int64 {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	return c.checkInterval
+}
+
+// SetCheckInterval sets the interval to poll the URL for changes.
+// The new interval is applied the next time polling is started.
+//	Parameters:
+//		- interval int64 a new interval in milliseconds.
+func (c *HttpConfigReader) SetCheckInterval(interval int64) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.checkInterval = interval
+}
+
+// SetHttpClient sets HTTP client used to fetch configuration.
+// By default a client with the configured timeout is used.
+//	Parameters:
+//		- client *http.Client a HTTP client or nil to use the default one.
+func (c *HttpConfigReader) SetHttpClient(client *http.Client) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.client = client
+}
+
+// ReadContent fetches configuration content from the URL. When the server is not available
+// or responds with an error, the last fetched content from memory or disk cache is returned.
+//	Parameters:
+//		- ctx context.Context
+//		- correlationId string transaction id to trace execution through call chain.
+//	Returns: string, error the configuration content or error if it cannot be fetched nor found in cache.
+func (c *HttpConfigReader) ReadContent(ctx context.Context, correlationId string) (string, error) {
+	content, _, _, err := c.fetch(ctx, correlationId)
+	return content, err
+}
+
+// fetch requests configuration content and reports whether it differs from the previous one.
+// The raw content is kept in memory, since it can be parameterized only on reading.
+func (c *HttpConfigReader) fetch(ctx context.Context, correlationId string) (string, string, bool, error) {
+	c.mtx.Lock()
+	uri := c.url
+	etag := c.etag
+	previous := c.content
+	previousType := c.contentType
+	client := c.client
+	if client == nil {
+		client = &http.Client{Timeout: time.Duration(c.timeout) * time.Millisecond}
+	}
+	c.mtx.Unlock()
+
+	if uri == "" {
+		return "", "", false, errors.NewConfigError(correlationId, "NO_URL", "Missing config URL")
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, uri, nil)
+	if err != nil {
+		return "", "", false, errors.NewConfigError(correlationId, "INVALID_URL", "Invalid config URL "+uri).
+			WithDetails("url", uri).WithCause(err)
+	}
+	if etag != "" && previous != "" {
+		req.Header.Set("If-None-Match", etag)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return c.fallback(correlationId, errors.NewConnectionError(
+			correlationId, "CONNECT_FAILED", "Failed to fetch config from "+uri,
+		).WithDetails("url", uri).WithCause(err))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotModified && previous != "" {
+		return previous, previousType, false, nil
+	}
+	if resp.StatusCode != http.StatusOK {
+		return c.fallback(correlationId, errors.NewConnectionError(
+			correlationId, "INVALID_STATUS", fmt.Sprintf("Failed to fetch config from %s: status %d", uri, resp.StatusCode),
+		).WithDetails("url", uri).WithDetails("status", resp.StatusCode))
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return c.fallback(correlationId, errors.NewConnectionError(
+			correlationId, "READ_FAILED", "Failed to read config from "+uri,
+		).WithDetails("url", uri).WithCause(err))
+	}
+	content := string(body)
+	contentType := resp.Header.Get("Content-Type")
+
+	c.mtx.Lock()
+	c.content = content
+	c.contentType = contentType
+	c.etag = resp.Header.Get("ETag")
+	c.mtx.Unlock()
+
+	return content, contentType, content != previous, nil
+}
+
+// fallback returns the last fetched content from memory or disk cache when fetching fails.
+// Loading the disk cache is not reported as a change since the configuration wasn't updated.
+func (c *HttpConfigReader) fallback(correlationId string, cause error) (string, string, bool, error) {
+	c.mtx.Lock()
+	content := c.content
+	contentType := c.contentType
+	cachePath := c.cachePath
+	c.mtx.Unlock()
+
+	if content != "" {
+		return content, contentType, false, nil
+	}
+	if cachePath != "" {
+		if b, err := os.ReadFile(cachePath); err == nil {
+			c.mtx.Lock()
+			c.content = string(b)
+			c.mtx.Unlock()
+			return string(b), "", false, nil
+		}
+	}
+	return "", "", false, cause
+}
+
+// writeHttpConfigCache atomically replaces the cache file.
+func writeHttpConfigCache(path string, content []byte) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+
+	file, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(content)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(file.Name(), path)
+	}
+	if err != nil {
+		_ = os.Remove(file.Name())
+	}
+	return err
+}
+
+// ReadObject fetches configuration, parameterizes its content and converts it into an object.
+// When the fetched content cannot be parameterized or parsed, the last good content is used,
+// so a malformed response doesn't replace the last good configuration.
+//	Parameters:
+//		- ctx context.Context
+//		- correlationId string transaction id to trace execution through call chain.
+//		- parameters *cconfig.ConfigParams values to parameters the configuration.
+//	Returns: any, error a configuration object and error.
+func (c *HttpConfigReader) ReadObject(ctx context.Context, correlationId string,
+	parameters *cconfig.ConfigParams) (any, error) {
+
+	content, contentType, _, err := c.fetch(ctx, correlationId)
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := c.readContentObject(correlationId, content, contentType, parameters)
+	if err != nil {
+		c.mtx.Lock()
+		good := c.good
+		goodType := c.goodType
+		c.mtx.Unlock()
+
+		if good == "" || good == content {
+			return nil, err
+		}
+		if value, goodErr := c.readContentObject(correlationId, good, goodType, parameters); goodErr == nil {
+			return value, nil
+		}
+		return nil, err
+	}
+
+	c.mtx.Lock()
+	changed := c.good != content
+	c.good = content
+	c.goodType = contentType
+	cachePath := c.cachePath
+	c.mtx.Unlock()
+
+	if cachePath != "" && changed {
+		// The disk cache is best effort and doesn't fail reading of fetched configuration
+		_ = writeHttpConfigCache(cachePath, []byte(content))
+	}
+	return value, nil
+}
+
+// readContentObject parameterizes content and converts it into an object.
+func (c *HttpConfigReader) readContentObject(correlationId string, content string, contentType string,
+	parameters *cconfig.ConfigParams) (any, error) {
+
+	data, err := c.Parameterize(content, parameters)
+	if err != nil {
+		return nil, err
+	}
+	return c.parseContent(correlationId, data, contentType)
+}
+
+// parseContent converts parameterized content into an object.
+// Content that is not a valid JSON or YAML object is reported as an error.
+func (c *HttpConfigReader) parseContent(correlationId string, data string, contentType string) (any, error) {
+	if c.detectFormat(data, contentType) == "yaml" {
+		var m any
+		if err := yaml.Unmarshal([]byte(data), &m); err != nil {
+			return nil, errors.NewConfigError(correlationId, "INVALID_YAML", "Failed to parse YAML config").
+				WithCause(err)
+		}
+		if _, ok := m.(map[any]any); !ok && m != nil {
+			return nil, errors.NewConfigError(correlationId, "INVALID_YAML", "YAML config must be an object")
+		}
+		return m, nil
+	}
+
+	var m map[string]any
+	if err := json.Unmarshal([]byte(data), &m); err != nil {
+		return nil, errors.NewConfigError(correlationId, "INVALID_JSON", "Failed to parse JSON config").
+			WithCause(err)
+	}
+	return m, nil
+}
+
+// detectFormat selects configuration format by the configured format,
+// response content type, URL extension or the content itself.
+func (c *HttpConfigReader) detectFormat(content string, contentType string) string {
+	c.mtx.Lock()
+	format := strings.ToLower(c.format)
+	uri := c.url
+	c.mtx.Unlock()
+	contentType = strings.ToLower(contentType)
+
+	switch format {
+	case "yaml", "yml":
+		return "yaml"
+	case "json":
+		return "json"
+	}
+
+	if strings.Contains(contentType, "yaml") {
+		return "yaml"
+	}
+	if strings.Contains(contentType, "json") {
+		return "json"
+	}
+
+	if u, err := url.Parse(uri); err == nil {
+		switch strings.ToLower(filepath.Ext(u.Path)) {
+		case ".yml", ".yaml":
+			return "yaml"
+		case ".json":
+			return "json"
+		}
+	}
+
+	trimmed := strings.TrimSpace(content)
+	if strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+		return "json"
+	}
+	return "yaml"
+}
+
+// ReadConfig fetches configuration from the URL, parameterize
+// it with given values and returns a new ConfigParams object.
+//	Parameters:
+//		- ctx context.Context
+//		- correlationId string transaction id to trace execution through call chain.
+//		- parameters *cconfig.ConfigParams values to parameters the configuration.
+//	Returns: *cconfig.ConfigParams, error
+func (c *HttpConfigReader) ReadConfig(ctx context.Context, correlationId string,
+	parameters *cconfig.ConfigParams) (result *cconfig.ConfigParams, err error) {
+
+	defer func() {
+		if r := recover(); r != nil {
+			var ok bool
+			err, ok = r.(error)
+			if !ok {
+				err = fmt.Errorf("pkg: %v", r)
+			}
+		}
+	}()
+
+	value, err := c.ReadObject(ctx, correlationId, parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	config := cconfig.NewConfigParamsFromValue(value)
+	if err = c.ValidateConfig(correlationId, config); err != nil {
+		return nil, err
+	}
+	c.rememberConfig(parameters, config)
+	return config, nil
+}
+
+// AddChangeListener adds a listener that will be notified when remote configuration is changed.
+// Polling of the URL starts when the first listener is added.
+//	Parameters:
+//		- ctx context.Context
+//		- listener crun.INotifiable a listener to be added.
+func (c *HttpConfigReader) AddChangeListener(ctx context.Context, listener crun.INotifiable) {
+	c.ConfigReader.AddChangeListener(ctx, listener)
+	if c.ChangeListenersCount() > 0 {
+		c.startPolling()
+	}
+}
+
+// RemoveChangeListener removes a previously added change listener.
+// Polling of the URL stops when the last listener is removed.
+//	Parameters:
+//		- ctx context.Context
+//		- listener crun.INotifiable a listener to be removed.
+func (c *HttpConfigReader) RemoveChangeListener(ctx context.Context, listener crun.INotifiable) {
+	c.ConfigReader.RemoveChangeListener(ctx, listener)
+	if c.ChangeListenersCount() == 0 {
+		c.stopPolling()
+	}
+}
+
+func (c *HttpConfigReader) startPolling() {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if c.stopWatch != nil {
+		return
+	}
+
+	interval := c.checkInterval
+	if interval <= 0 {
+		interval = 10000
+	}
+
+	stop := make(chan struct{})
+	c.stopWatch = stop
+	initialized := c.content != ""
+
+	go func() {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() {
+			<-stop
+			cancel()
+		}()
+
+		// The first fetch sets the baseline unless content was already read
+		if !initialized {
+			_, _, _, _ = c.fetch(ctx, "")
+		}
+
+		ticker := time.NewTicker(time.Duration(interval) * time.Millisecond)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-stop:
+				return
+			case <-ticker.C:
+				_, _, changed, err := c.fetch(ctx, "")
+				if err == nil && changed {
+					c.NotifyChangeListeners(context.Background(), "",
+						crun.NewParametersFromTuples("url", c.Url()))
+				}
+			}
+		}
+	}()
+}
+
+func (c *HttpConfigReader) stopPolling() {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if c.stopWatch != nil {
+		close(c.stopWatch)
+		c.stopWatch = nil
+	}
+}
@@ -0,0 +1,17 @@
+package config
+
+import (
+	"context"
+
+	cconfig "github.com/pip-services3-gox/pip-services3-commons-gox/config"
+)
+
+// IConfigSourcesReader interface for configuration readers that are able to tell
+// where every configuration value came from.
+type IConfigSourcesReader interface {
+
+	// ReadConfigWithSources reads configuration, parameterize it with given values
+	// and returns sources of configuration values by their keys.
+	ReadConfigWithSources(ctx context.Context, correlationId string,
+		parameters *cconfig.ConfigParams) (*cconfig.ConfigParams, map[string]*ConfigSource, error)
+}
@@ -0,0 +1,17 @@
+package config
+
+import "context"
+
+// ISecretResolver interface for resolvers of secret references in configuration values.
+// References have the form ${secret:<resolver>:<reference>} or ${<resolver>:<reference>},
+// for instance ${secret:file:/run/secrets/db} or ${env:DB_PASS}.
+type ISecretResolver interface {
+
+	// ResolveSecret resolves a secret by its reference.
+	//	Parameters:
+	//		- ctx context.Context
+	//		- correlationId string transaction id to trace execution through call chain.
+	//		- reference string a secret reference without resolver name, like a file path or a variable name.
+	//	Returns: string, error the secret value and error.
+	ResolveSecret(ctx context.Context, correlationId string, reference string) (string, error)
+}
@@ -3,15 +3,17 @@ package config
 import (
 	"context"
 	"fmt"
-	"io/ioutil"
+	"regexp"
+	"strings"
 
 	cconfig "github.com/pip-services3-gox/pip-services3-commons-gox/config"
 	"github.com/pip-services3-gox/pip-services3-commons-gox/convert"
-	"github.com/pip-services3-gox/pip-services3-commons-gox/errors"
 )
 
 // JsonConfigReader is a config reader that reads configuration from JSON file.
 // The reader supports parameterization using Handlebar template engine.
+// Other JSON files can be included using {"$include": "path"} objects
+// with paths relative to the including file.
 //	Configuration parameters:
 //		- path: path to configuration file
 //		- parameters: this entire section is used as template parameters
@@ -20,7 +22,7 @@ import (
 //	see FileConfigReader
 //	Example:
 //		======== config.json ======
-//		{ "key1": "{{KEY1_VALUE}}", "key2": "{{KEY2_VALUE}}" }
+//		{ "key1": "{{KEY1_VALUE}}", "key2": "{{KEY2_VALUE}}", "key3": { "$include": "key3.json" } }
 //		===========================
 //
 //		configReader := NewJsonConfigReader("config.json")
@@ -33,18 +35,22 @@ type JsonConfigReader struct {
 // NewEmptyJsonConfigReader creates a new instance of the config reader.
 //	Returns: *JsonConfigReader
 func NewEmptyJsonConfigReader() *JsonConfigReader {
-	return &JsonConfigReader{
+	c := &JsonConfigReader{
 		FileConfigReader: NewEmptyFileConfigReader(),
 	}
+	c.trackChanges(c)
+	return c
 }
 
 // NewJsonConfigReader creates a new instance of the config reader.
 //	Parameters: path string a path to configuration file.
 //	Returns: *JsonConfigReader
 func NewJsonConfigReader(path string) *JsonConfigReader {
-	return &JsonConfigReader{
+	c := &JsonConfigReader{
 		FileConfigReader: NewFileConfigReader(path),
 	}
+	c.trackChanges(c)
+	return c
 }
 
 // ReadObject reads configuration file, parameterizes its content and converts it into JSON object.
@@ -56,22 +62,11 @@ func NewJsonConfigReader(path string) *JsonConfigReader {
 func (c *JsonConfigReader) ReadObject(ctx context.Context, correlationId string,
 	parameters *cconfig.ConfigParams) (any, error) {
 
-	if c.Path() == "" {
-		return nil, errors.NewConfigError(correlationId, "NO_PATH", "Missing config file path")
-	}
-
-	b, err := ioutil.ReadFile(c.Path())
+	data, err := c.ReadContent(correlationId, expandJsonIncludes)
 	if err != nil {
-		err = errors.NewFileError(
-			correlationId,
-			"READ_FAILED",
-			"Failed reading configuration "+c.Path()+": "+err.Error(),
-		).
-			WithDetails("path", c.Path()).WithCause(err)
 		return nil, err
 	}
 
-	data := string(b)
 	data, err = c.Parameterize(data, parameters)
 	if err != nil {
 		return nil, err
@@ -106,7 +101,36 @@ func (c *JsonConfigReader) ReadConfig(ctx context.Context, correlationId string,
 	}
 
 	config := cconfig.NewConfigParamsFromValue(value)
-	return config, err
+	if err = c.ResolveSecrets(ctx, correlationId, config); err != nil {
+		return nil, err
+	}
+	if err = c.ValidateConfig(correlationId, config); err != nil {
+		return nil, err
+	}
+	c.rememberConfig(parameters, config)
+	return config, nil
+}
+
+// ReadConfigWithSources reads configuration from a file, parameterize it with given values
+// and returns sources of configuration values with files and lines they came from.
+//	Parameters:
+//		- ctx context.Context
+//		- correlationId string transaction id to trace execution through call chain.
+//		- parameters *cconfig.ConfigParams values to parameters the configuration.
+//	Returns: *cconfig.ConfigParams, map[string]*ConfigSource, error
+func (c *JsonConfigReader) ReadConfigWithSources(ctx context.Context, correlationId string,
+	parameters *cconfig.ConfigParams) (*cconfig.ConfigParams, map[string]*ConfigSource, error) {
+
+	config, err := c.ReadConfig(ctx, correlationId, parameters)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sources, err := c.LocateSources(correlationId, "json", expandJsonIncludes, config)
+	if err != nil {
+		return nil, nil, err
+	}
+	return config, sources, nil
 }
 
 // ReadJsonObject reads configuration file, parameterizes its content and converts it into JSON object.
@@ -137,3 +161,23 @@ func ReadJsonConfig(ctx context.Context, correlationId string, path string,
 	reader := NewJsonConfigReader(path)
 	return reader.ReadConfig(ctx, correlationId, parameters)
 }
+
+var jsonIncludePattern = regexp.MustCompile(`\{\s*"\$include"\s*:\s*"([^"]+)"\s*\}`)
+
+// expandJsonIncludes replaces {"$include": "path"} objects with content of the included files.
+func expandJsonIncludes(content string, include func(path string) (string, error)) (string, error) {
+	var err error
+	result := jsonIncludePattern.ReplaceAllStringFunc(content, func(match string) string {
+		if err != nil {
+			return match
+		}
+		path := jsonIncludePattern.FindStringSubmatch(match)[1]
+		var included string
+		included, err = include(path)
+		return strings.TrimSpace(included)
+	})
+	if err != nil {
+		return "", err
+	}
+	return result, nil
+}
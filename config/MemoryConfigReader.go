@@ -4,7 +4,6 @@ import (
 	"context"
 
 	cconfig "github.com/pip-services3-gox/pip-services3-commons-gox/config"
-	crun "github.com/pip-services3-gox/pip-services3-commons-gox/run"
 	"github.com/pip-services3-gox/pip-services3-expressions-gox/mustache"
 )
 
@@ -23,6 +22,7 @@ import (
 //		res, err := configReader.ReadConfig(context.Background(), "123", parameters);
 //			Possible result: connection.host=10.1.1.100;connection.port=8080
 type MemoryConfigReader struct {
+	*ConfigReader
 	config *cconfig.ConfigParams
 }
 
@@ -30,7 +30,8 @@ type MemoryConfigReader struct {
 //	Returns: *MemoryConfigReader
 func NewEmptyMemoryConfigReader() *MemoryConfigReader {
 	return &MemoryConfigReader{
-		config: cconfig.NewEmptyConfigParams(),
+		ConfigReader: NewConfigReader(),
+		config:       cconfig.NewEmptyConfigParams(),
 	}
 }
 
@@ -39,7 +40,8 @@ func NewEmptyMemoryConfigReader() *MemoryConfigReader {
 //	Returns: *MemoryConfigReader
 func NewMemoryConfigReader(config *cconfig.ConfigParams) *MemoryConfigReader {
 	return &MemoryConfigReader{
-		config: config,
+		ConfigReader: NewConfigReader(),
+		config:       config,
 	}
 }
 
@@ -61,6 +63,7 @@ func (c *MemoryConfigReader) Configure(ctx context.Context, config *cconfig.Conf
 func (c *MemoryConfigReader) ReadConfig(ctx context.Context, correlationId string,
 	parameters *cconfig.ConfigParams) (*cconfig.ConfigParams, error) {
 
+	var result *cconfig.ConfigParams
 	if parameters != nil {
 		template := c.config.String()
 		value := parameters.Value()
@@ -75,20 +78,40 @@ func (c *MemoryConfigReader) ReadConfig(ctx context.Context, correlationId strin
 			return nil, err
 		}
 
-		result := cconfig.NewConfigParamsFromString(config)
-		return result, nil
+		result = cconfig.NewConfigParamsFromString(config)
 	} else {
-		result := cconfig.NewConfigParamsFromValue(c.config.Value())
-		return result, nil
+		result = cconfig.NewConfigParamsFromValue(c.config.Value())
 	}
-}
 
-// AddChangeListener - Adds a listener that will be notified when configuration is changed
-func (c *MemoryConfigReader) AddChangeListener(ctx context.Context, listener crun.INotifiable) {
-	// Do nothing...
+	if err := c.ValidateConfig(correlationId, result); err != nil {
+		return nil, err
+	}
+	return result, nil
 }
 
-// RemoveChangeListener - Remove a previously added change listener.
-func (c *MemoryConfigReader) RemoveChangeListener(ctx context.Context, listener crun.INotifiable) {
-	// Do nothing...
+// ReadConfigWithSources reads configuration, parameterize it with given values
+// and returns sources of configuration values with template parameters they came from.
+//	Parameters:
+//		- ctx context.Context
+//		- correlationId string transaction id to trace execution through call chain.
+//		- parameters *cconfig.ConfigParams values to parameters
+//			the configuration or null to skip parameterization.
+//	Returns: *cconfig.ConfigParams, map[string]*ConfigSource, error
+func (c *MemoryConfigReader) ReadConfigWithSources(ctx context.Context, correlationId string,
+	parameters *cconfig.ConfigParams) (*cconfig.ConfigParams, map[string]*ConfigSource, error) {
+
+	config, err := c.ReadConfig(ctx, correlationId, parameters)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sources := make(map[string]*ConfigSource)
+	for _, key := range config.Keys() {
+		source := &ConfigSource{Reader: "memory"}
+		if parameters != nil {
+			source.Parameter = findTemplateParameter(c.config.GetAsString(key))
+		}
+		sources[key] = source
+	}
+	return config, sources, nil
 }
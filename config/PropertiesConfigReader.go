@@ -0,0 +1,263 @@
+package config
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	cconfig "github.com/pip-services3-gox/pip-services3-commons-gox/config"
+	"github.com/pip-services3-gox/pip-services3-commons-gox/errors"
+)
+
+// PropertiesConfigReader is a config reader that reads configuration from Java-style .properties file.
+// Property keys use dot notation to define configuration sections.
+// The reader supports parameterization using Handlebars template engine.
+//	Configuration parameters:
+//		- path: path to configuration file
+//		- parameters: this entire section is used as template parameters
+//		...
+//	see IConfigReader
+//	see FileConfigReader
+//	Example:
+//		======== config.properties ======
+//		connection.host={{HOST}}
+//		connection.port: 8080
+//		=================================
+//
+//		configReader := NewPropertiesConfigReader("config.properties")
+//		parameters := NewConfigParamsFromTuples("HOST", "localhost");
+//		res, err := configReader.ReadConfig(context.Background(), "123", parameters);
+//			// Result: connection.host=localhost;connection.port=8080
+type PropertiesConfigReader struct {
+	*FileConfigReader
+}
+
+// NewEmptyPropertiesConfigReader creates a new instance of the config reader.
+//	Returns: *PropertiesConfigReader
+func NewEmptyPropertiesConfigReader() *PropertiesConfigReader {
+	c := &PropertiesConfigReader{
+		FileConfigReader: NewEmptyFileConfigReader(),
+	}
+	c.trackChanges(c)
+	return c
+}
+
+// NewPropertiesConfigReader creates a new instance of the config reader.
+//	Parameters: path string a path to configuration file.
+//	Returns: *PropertiesConfigReader
+func NewPropertiesConfigReader(path string) *PropertiesConfigReader {
+	c := &PropertiesConfigReader{
+		FileConfigReader: NewFileConfigReader(path),
+	}
+	c.trackChanges(c)
+	return c
+}
+
+// ReadObject reads configuration file, parameterizes its content and converts it into a map of properties.
+//	Parameters:
+//		- ctx context.Context
+//		- correlationId string transaction id to trace execution through call chain.
+//		- parameters *cconfig.ConfigParams values to parameters the configuration.
+//	Returns: any, error a map with configuration properties and error.
+func (c *PropertiesConfigReader) ReadObject(ctx context.Context, correlationId string,
+	parameters *cconfig.ConfigParams) (any, error) {
+
+	data, err := c.ReadContent(correlationId, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err = c.Parameterize(data, parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	m, err := parseProperties(data)
+	if err != nil {
+		return nil, errors.NewConfigError(
+			correlationId,
+			"INVALID_PROPERTIES",
+			"Failed parsing configuration "+c.Path()+": "+err.Error(),
+		).WithDetails("path", c.Path()).WithCause(err)
+	}
+
+	return m, nil
+}
+
+// ReadConfig reads configuration from a file, parameterize it with given values and returns a new ConfigParams object.
+//	Parameters:
+//		- ctx context.Context
+//		- correlationId string transaction id to trace execution through call chain.
+//		- parameters *cconfig.ConfigParams values to parameters the configuration.
+//	Returns: *cconfig.ConfigParams, error
+func (c *PropertiesConfigReader) ReadConfig(ctx context.Context, correlationId string,
+	parameters *cconfig.ConfigParams) (result *cconfig.ConfigParams, err error) {
+
+	defer func() {
+		if r := recover(); r != nil {
+			var ok bool
+			err, ok = r.(error)
+			if !ok {
+				err = fmt.Errorf("pkg: %v", r)
+			}
+		}
+	}()
+
+	value, err := c.ReadObject(ctx, correlationId, parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	config := cconfig.NewConfigParams(value.(map[string]string))
+	if err = c.ResolveSecrets(ctx, correlationId, config); err != nil {
+		return nil, err
+	}
+	if err = c.ValidateConfig(correlationId, config); err != nil {
+		return nil, err
+	}
+	c.rememberConfig(parameters, config)
+	return config, nil
+}
+
+// ReadConfigWithSources reads configuration from a file, parameterize it with given values
+// and returns sources of configuration values with files and lines they came from.
+//	Parameters:
+//		- ctx context.Context
+//		- correlationId string transaction id to trace execution through call chain.
+//		- parameters *cconfig.ConfigParams values to parameters the configuration.
+//	Returns: *cconfig.ConfigParams, map[string]*ConfigSource, error
+func (c *PropertiesConfigReader) ReadConfigWithSources(ctx context.Context, correlationId string,
+	parameters *cconfig.ConfigParams) (*cconfig.ConfigParams, map[string]*ConfigSource, error) {
+
+	config, err := c.ReadConfig(ctx, correlationId, parameters)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sources, err := c.LocateSources(correlationId, "properties", nil, config)
+	if err != nil {
+		return nil, nil, err
+	}
+	return config, sources, nil
+}
+
+// parseProperties parses content of .properties file.
+// It supports "=", ":" and whitespace separators, "#" and "!" comments,
+// line continuations and escape sequences including unicode escapes.
+func parseProperties(data string) (map[string]string, error) {
+	result := make(map[string]string)
+
+	lines := strings.Split(strings.ReplaceAll(data, "\r\n", "\n"), "\n")
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimLeft(lines[i], " \t\f")
+		if line == "" || line[0] == '#' || line[0] == '!' {
+			continue
+		}
+
+		// Join continuation lines
+		for endsWithContinuation(line) && i+1 < len(lines) {
+			i++
+			line = line[:len(line)-1] + strings.TrimLeft(lines[i], " \t\f")
+		}
+		if endsWithContinuation(line) {
+			line = line[:len(line)-1]
+		}
+
+		// Find the end of the key
+		keyEnd := len(line)
+		for j := 0; j < len(line); j++ {
+			if line[j] == '\\' {
+				j++
+				continue
+			}
+			if line[j] == '=' || line[j] == ':' || line[j] == ' ' || line[j] == '\t' || line[j] == '\f' {
+				keyEnd = j
+				break
+			}
+		}
+
+		// Skip the separator
+		rest := strings.TrimLeft(line[keyEnd:], " \t\f")
+		if rest != "" && (rest[0] == '=' || rest[0] == ':') {
+			rest = strings.TrimLeft(rest[1:], " \t\f")
+		}
+
+		key, err := unescapeProperty(line[:keyEnd])
+		if err != nil {
+			return nil, err
+		}
+		value, err := unescapeProperty(rest)
+		if err != nil {
+			return nil, err
+		}
+		result[key] = value
+	}
+
+	return result, nil
+}
+
+// endsWithContinuation checks if the line ends with an odd number of backslashes.
+func endsWithContinuation(line string) bool {
+	count := 0
+	for i := len(line) - 1; i >= 0 && line[i] == '\\'; i-- {
+		count++
+	}
+	return count%2 == 1
+}
+
+// unescapeProperty replaces escape sequences in property key or value.
+func unescapeProperty(value string) (string, error) {
+	if !strings.Contains(value, "\\") {
+		return value, nil
+	}
+
+	builder := strings.Builder{}
+	for i := 0; i < len(value); i++ {
+		ch := value[i]
+		if ch != '\\' || i+1 >= len(value) {
+			builder.WriteByte(ch)
+			continue
+		}
+
+		i++
+		switch value[i] {
+		case 't':
+			builder.WriteByte('\t')
+		case 'n':
+			builder.WriteByte('\n')
+		case 'r':
+			builder.WriteByte('\r')
+		case 'f':
+			builder.WriteByte('\f')
+		case 'u':
+			if i+4 >= len(value) {
+				return "", fmt.Errorf("malformed unicode escape in %q", value)
+			}
+			code, err := strconv.ParseUint(value[i+1:i+5], 16, 32)
+			if err != nil {
+				return "", fmt.Errorf("malformed unicode escape in %q", value)
+			}
+			builder.WriteRune(rune(code))
+			i += 4
+		default:
+			builder.WriteByte(value[i])
+		}
+	}
+	return builder.String(), nil
+}
+
+// ReadPropertiesConfig reads configuration from a file,
+// parameterize it with given values and returns a new ConfigParams object.
+//	Parameters:
+//		- ctx context.Context
+//		- correlationId string transaction id to trace execution through call chain.
+//		- path string
+//		- parameters *cconfig.ConfigParams values to parameters the configuration.
+//	Returns: *cconfig.ConfigParams, error
+func ReadPropertiesConfig(ctx context.Context, correlationId string, path string,
+	parameters *cconfig.ConfigParams) (*cconfig.ConfigParams, error) {
+
+	reader := NewPropertiesConfigReader(path)
+	return reader.ReadConfig(ctx, correlationId, parameters)
+}
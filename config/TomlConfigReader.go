@@ -0,0 +1,170 @@
+package config
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/BurntSushi/toml"
+	cconfig "github.com/pip-services3-gox/pip-services3-commons-gox/config"
+	"github.com/pip-services3-gox/pip-services3-commons-gox/errors"
+)
+
+// TomlConfigReader is a config reader that reads configuration from TOML file.
+// The reader supports parameterization using Handlebars template engine.
+//	Configuration parameters:
+//		- path: path to configuration file
+//		- parameters: this entire section is used as template parameters
+//		...
+//	see IConfigReader
+//	see FileConfigReader
+//	Example:
+//		======== config.toml ======
+//		key1 = "{{KEY1_VALUE}}"
+//		key2 = "{{KEY2_VALUE}}"
+//		===========================
+//
+//		configReader := NewTomlConfigReader("config.toml")
+//		parameters := NewConfigParamsFromTuples("KEY1_VALUE", 123, "KEY2_VALUE", "ABC");
+//		res, err := configReader.ReadConfig(context.Background(), "123", parameters);
+//			// Result: key1=123;key2=ABC
+type TomlConfigReader struct {
+	*FileConfigReader
+}
+
+// NewEmptyTomlConfigReader creates a new instance of the config reader.
+//	Returns: *TomlConfigReader
+func NewEmptyTomlConfigReader() *TomlConfigReader {
+	c := &TomlConfigReader{
+		FileConfigReader: NewEmptyFileConfigReader(),
+	}
+	c.trackChanges(c)
+	return c
+}
+
+// NewTomlConfigReader creates a new instance of the config reader.
+//	Parameters: path string a path to configuration file.
+//	Returns: *TomlConfigReader
+func NewTomlConfigReader(path string) *TomlConfigReader {
+	c := &TomlConfigReader{
+		FileConfigReader: NewFileConfigReader(path),
+	}
+	c.trackChanges(c)
+	return c
+}
+
+// ReadObject reads configuration file, parameterizes its content and converts it into JSON object.
+//	Parameters:
+//		- ctx context.Context
+//		- correlationId string transaction id to trace execution through call chain.
+//		- parameters *cconfig.ConfigParams values to parameters the configuration.
+//	Returns: any, error a JSON object with configuration and error.
+func (c *TomlConfigReader) ReadObject(ctx context.Context, correlationId string,
+	parameters *cconfig.ConfigParams) (any, error) {
+
+	data, err := c.ReadContent(correlationId, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err = c.Parameterize(data, parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	m := make(map[string]any)
+	if _, err = toml.Decode(data, &m); err != nil {
+		return nil, errors.NewConfigError(
+			correlationId,
+			"INVALID_TOML",
+			"Failed parsing configuration "+c.Path()+": "+err.Error(),
+		).WithDetails("path", c.Path()).WithCause(err)
+	}
+
+	return m, nil
+}
+
+// ReadConfig reads configuration from a file, parameterize it with given values and returns a new ConfigParams object.
+//	Parameters:
+//		- ctx context.Context
+//		- correlationId string transaction id to trace execution through call chain.
+//		- parameters *cconfig.ConfigParams values to parameters the configuration.
+//	Returns: *cconfig.ConfigParams, error
+func (c *TomlConfigReader) ReadConfig(ctx context.Context, correlationId string,
+	parameters *cconfig.ConfigParams) (result *cconfig.ConfigParams, err error) {
+
+	defer func() {
+		if r := recover(); r != nil {
+			var ok bool
+			err, ok = r.(error)
+			if !ok {
+				err = fmt.Errorf("pkg: %v", r)
+			}
+		}
+	}()
+
+	value, err := c.ReadObject(ctx, correlationId, parameters)
+	if err != nil {
+		return nil, err
+	}
+
+	config := cconfig.NewConfigParamsFromValue(value)
+	if err = c.ResolveSecrets(ctx, correlationId, config); err != nil {
+		return nil, err
+	}
+	if err = c.ValidateConfig(correlationId, config); err != nil {
+		return nil, err
+	}
+	c.rememberConfig(parameters, config)
+	return config, nil
+}
+
+// ReadConfigWithSources reads configuration from a file, parameterize it with given values
+// and returns sources of configuration values with files and lines they came from.
+//	Parameters:
+//		- ctx context.Context
+//		- correlationId string transaction id to trace execution through call chain.
+//		- parameters *cconfig.ConfigParams values to parameters the configuration.
+//	Returns: *cconfig.ConfigParams, map[string]*ConfigSource, error
+func (c *TomlConfigReader) ReadConfigWithSources(ctx context.Context, correlationId string,
+	parameters *cconfig.ConfigParams) (*cconfig.ConfigParams, map[string]*ConfigSource, error) {
+
+	config, err := c.ReadConfig(ctx, correlationId, parameters)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sources, err := c.LocateSources(correlationId, "toml", nil, config)
+	if err != nil {
+		return nil, nil, err
+	}
+	return config, sources, nil
+}
+
+// ReadTomlObject reads configuration file, parameterizes its content and converts it into JSON object.
+//	Parameters:
+//		- ctx context.Context
+//		- correlationId string transaction id to trace execution through call chain.
+//		- path string
+//		- parameters *cconfig.ConfigParams values to parameters the configuration.
+//	Returns: any, error a JSON object with configuration.
+func ReadTomlObject(ctx context.Context, correlationId string, path string,
+	parameters *cconfig.ConfigParams) (any, error) {
+
+	reader := NewTomlConfigReader(path)
+	return reader.ReadObject(ctx, correlationId, parameters)
+}
+
+// ReadTomlConfig reads configuration from a file,
+// parameterize it with given values and returns a new ConfigParams object.
+//	Parameters:
+//		- ctx context.Context
+//		- correlationId string transaction id to trace execution through call chain.
+//		- path string
+//		- parameters *cconfig.ConfigParams values to parameters the configuration.
+//	Returns: *cconfig.ConfigParams, error
+func ReadTomlConfig(ctx context.Context, correlationId string, path string,
+	parameters *cconfig.ConfigParams) (*cconfig.ConfigParams, error) {
+
+	reader := NewTomlConfigReader(path)
+	return reader.ReadConfig(ctx, correlationId, parameters)
+}
@@ -3,15 +3,17 @@ package config
 import (
 	"context"
 	"fmt"
-	"io/ioutil"
+	"regexp"
+	"strings"
 
 	cconfig "github.com/pip-services3-gox/pip-services3-commons-gox/config"
-	"github.com/pip-services3-gox/pip-services3-commons-gox/errors"
 	"gopkg.in/yaml.v2"
 )
 
 // YamlConfigReader is a config reader that reads configuration from YAML file.
 // The reader supports parameterization using Handlebars template engine.
+// Other YAML files can be included using "!include path" directives
+// with paths relative to the including file.
 //	Configuration parameters:
 //		- path: path to configuration file
 //		- parameters: this entire section is used as template parameters
@@ -22,6 +24,7 @@ import (
 //		======== config.yml ======
 //		key1: "{{KEY1_VALUE}}"
 //		key2: "{{KEY2_VALUE}}"
+//		key3: !include key3.yml
 //		===========================
 //
 //		configReader := NewYamlConfigReader("config.yml")
@@ -35,18 +38,22 @@ type YamlConfigReader struct {
 // NewEmptyYamlConfigReader сreates a new instance of the config reader.
 //	Returns: *YamlConfigReader
 func NewEmptyYamlConfigReader() *YamlConfigReader {
-	return &YamlConfigReader{
+	c := &YamlConfigReader{
 		FileConfigReader: NewEmptyFileConfigReader(),
 	}
+	c.trackChanges(c)
+	return c
 }
 
 // NewYamlConfigReader creates a new instance of the config reader.
 //	Parameters: path string a path to configuration file.
 //	Returns: *YamlConfigReader
 func NewYamlConfigReader(path string) *YamlConfigReader {
-	return &YamlConfigReader{
+	c := &YamlConfigReader{
 		FileConfigReader: NewFileConfigReader(path),
 	}
+	c.trackChanges(c)
+	return c
 }
 
 // ReadObject reads configuration file, parameterizes its content and converts it into JSON object.
@@ -58,22 +65,11 @@ func NewYamlConfigReader(path string) *YamlConfigReader {
 func (c *YamlConfigReader) ReadObject(ctx context.Context, correlationId string,
 	parameters *cconfig.ConfigParams) (any, error) {
 
-	if c.Path() == "" {
-		return nil, errors.NewConfigError(correlationId, "NO_PATH", "Missing config file path")
-	}
-
-	b, err := ioutil.ReadFile(c.Path())
+	data, err := c.ReadContent(correlationId, expandYamlIncludes)
 	if err != nil {
-		err = errors.NewFileError(
-			correlationId,
-			"READ_FAILED",
-			"Failed reading configuration "+c.Path()+": "+err.Error(),
-		).
-			WithDetails("path", c.Path()).WithCause(err)
 		return nil, err
 	}
 
-	data := string(b)
 	data, err = c.Parameterize(data, parameters)
 	if err != nil {
 		return nil, err
@@ -114,7 +110,36 @@ func (c *YamlConfigReader) ReadConfig(ctx context.Context, correlationId string,
 	}
 
 	config := cconfig.NewConfigParamsFromValue(value)
-	return config, err
+	if err = c.ResolveSecrets(ctx, correlationId, config); err != nil {
+		return nil, err
+	}
+	if err = c.ValidateConfig(correlationId, config); err != nil {
+		return nil, err
+	}
+	c.rememberConfig(parameters, config)
+	return config, nil
+}
+
+// ReadConfigWithSources reads configuration from a file, parameterize it with given values
+// and returns sources of configuration values with files and lines they came from.
+//	Parameters:
+//		- ctx context.Context
+//		- correlationId string transaction id to trace execution through call chain.
+//		- parameters *cconfig.ConfigParams values to parameters the configuration.
+//	Returns: *cconfig.ConfigParams, map[string]*ConfigSource, error
+func (c *YamlConfigReader) ReadConfigWithSources(ctx context.Context, correlationId string,
+	parameters *cconfig.ConfigParams) (*cconfig.ConfigParams, map[string]*ConfigSource, error) {
+
+	config, err := c.ReadConfig(ctx, correlationId, parameters)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sources, err := c.LocateSources(correlationId, "yaml", expandYamlIncludes, config)
+	if err != nil {
+		return nil, nil, err
+	}
+	return config, sources, nil
 }
 
 // ReadYamlObject reads configuration file, parameterizes its content and converts it into JSON object.
@@ -145,3 +170,74 @@ func ReadYamlConfig(ctx context.Context, correlationId string, path string,
 	reader := NewYamlConfigReader(path)
 	return reader.ReadConfig(ctx, correlationId, parameters)
 }
+
+var yamlIncludePattern = regexp.MustCompile(`^([ \t]*)(-[ \t]+)?([^\s#!-][^#]*?:)?[ \t]*!include[ \t]+["']?([^"'\s]+)["']?[ \t]*$`)
+
+// expandYamlIncludes replaces "!include path" directives with content of the included files
+// indented to the position of the directive. The directive can be used as a value of a key,
+// as a list item or on its own line to merge the included content at the current level.
+func expandYamlIncludes(content string, include func(path string) (string, error)) (string, error) {
+	lines := strings.Split(content, "\n")
+	result := make([]string, 0, len(lines))
+
+	for _, line := range lines {
+		match := yamlIncludePattern.FindStringSubmatch(strings.TrimRight(line, "\r"))
+		if match == nil {
+			result = append(result, line)
+			continue
+		}
+
+		indent, dash, key, path := match[1], match[2], match[3], match[4]
+		included, err := include(path)
+		if err != nil {
+			return "", err
+		}
+		includedLines := yamlDocumentLines(included)
+
+		switch {
+		case key != "":
+			result = append(result, indent+dash+key)
+			result = append(result, indentYamlLines(includedLines, strings.Repeat(" ", len(indent)+len(dash)+2))...)
+		case dash != "" && len(includedLines) > 0:
+			result = append(result, indent+dash+includedLines[0])
+			result = append(result, indentYamlLines(includedLines[1:], strings.Repeat(" ", len(indent)+len(dash)))...)
+		default:
+			result = append(result, indentYamlLines(includedLines, indent)...)
+		}
+	}
+
+	return strings.Join(result, "\n"), nil
+}
+
+// yamlDocumentLines splits included YAML content into lines
+// skipping document markers and leading or trailing empty lines.
+func yamlDocumentLines(content string) []string {
+	result := make([]string, 0)
+	for _, line := range strings.Split(content, "\n") {
+		line = strings.TrimRight(line, "\r")
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "---" || trimmed == "..." {
+			continue
+		}
+		if len(result) == 0 && trimmed == "" {
+			continue
+		}
+		result = append(result, line)
+	}
+	for len(result) > 0 && strings.TrimSpace(result[len(result)-1]) == "" {
+		result = result[:len(result)-1]
+	}
+	return result
+}
+
+func indentYamlLines(lines []string, indent string) []string {
+	result := make([]string, len(lines))
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			result[i] = ""
+		} else {
+			result[i] = indent + line
+		}
+	}
+	return result
+}
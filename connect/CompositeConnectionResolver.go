@@ -2,6 +2,7 @@ package connect
 
 import (
 	"context"
+	"crypto/tls"
 
 	"github.com/pip-services3-gox/pip-services3-commons-gox/config"
 	cerr "github.com/pip-services3-gox/pip-services3-commons-gox/errors"
@@ -35,10 +36,16 @@ type ICompositeConnectionResolverOverrides interface {
 //			- host:                        host name or IP address
 //			- port:                        port number
 //			- uri:                         resource URI or connection string with all parameters in it
+//			- ssl:                         (optional) true to use TLS connection
+//			- ssl_ca_file:                 (optional) path to PEM file with CA certificates
+//			- ssl_server_name:             (optional) server name to verify the server certificate
+//			- ssl_insecure:                (optional) true to skip verification of the server certificate
 //		- credential(s):
 //			- store_key:                   (optional) a key to retrieve the credentials from [ICredentialStore]]
 //			- username:                    user name
 //			- password:                    user password
+//			- ssl_cert_file:               (optional) path to PEM file with client certificate
+//			- ssl_key_file:                (optional) path to PEM file with client private key
 //
 //	References
 //		- *:discovery:*:*:1.0          (optional) [IDiscovery]] services to resolve connections
@@ -66,6 +73,9 @@ type CompositeConnectionResolver struct {
 
 	// The list of supported protocols
 	SupportedProtocols []string
+
+	// The protocol profiles to validate and normalize connections (optional)
+	Profiles []*ConnectionProfile
 }
 
 // InheritCompositeConnectionResolver creates new CompositeConnectionResolver
@@ -101,14 +111,81 @@ func (c *CompositeConnectionResolver) SetReferences(ctx context.Context, referen
 	c.CredentialResolver.SetReferences(ctx, references)
 }
 
+// UseProfiles enables validation and normalization of connections by profiles of the specified protocols.
+// It also sets the supported protocols, and the default protocol and port from the first profile.
+//	see ConnectionProfiles
+//	Parameters:
+//		- protocols ...string protocols of registered connection profiles.
+//	Returns: error an UnsupportedError if any of the protocols has no registered profile.
+func (c *CompositeConnectionResolver) UseProfiles(protocols ...string) error {
+	profiles := make([]*ConnectionProfile, 0, len(protocols))
+	supportedProtocols := make([]string, 0, len(protocols))
+	for _, protocol := range protocols {
+		profile := ConnectionProfiles.Get(protocol)
+		if profile == nil {
+			return cerr.NewUnsupportedError(
+				"", "UNKNOWN_PROFILE", "Connection profile for "+protocol+" protocol is not registered",
+			).WithDetails("protocol", protocol)
+		}
+		profiles = append(profiles, profile)
+		supportedProtocols = append(supportedProtocols, profile.Protocol)
+		supportedProtocols = append(supportedProtocols, profile.Aliases...)
+	}
+
+	c.Profiles = profiles
+	c.SupportedProtocols = supportedProtocols
+	if len(profiles) > 0 {
+		c.DefaultProtocol = profiles[0].Protocol
+		c.DefaultPort = profiles[0].DefaultPort
+	}
+	return nil
+}
+
+// FindProfile finds a connection profile for the connection among configured profiles.
+//	Parameters:
+//		- connection *ConnectionParams connection parameters.
+//	Returns: *ConnectionProfile a found profile or nil if no profiles match the connection.
+func (c *CompositeConnectionResolver) FindProfile(connection *ConnectionParams) *ConnectionProfile {
+	if len(c.Profiles) == 0 || connection == nil {
+		return nil
+	}
+
+	protocol := connection.Protocol()
+	if uri := connection.Uri(); uri != "" {
+		protocol = ConnectionUtils.ParseUri(uri, protocol, 0).GetAsString(ConnectionParamProtocol)
+	}
+	if protocol == "" {
+		protocol = c.DefaultProtocol
+	}
+	if protocol == "" {
+		return c.Profiles[0]
+	}
+
+	for _, profile := range c.Profiles {
+		if profile.Matches(protocol) {
+			return profile
+		}
+	}
+	return nil
+}
+
 // Resolve connection options from connection and credential parameters.
 //		- correlationId     (optional) transaction id to trace execution through call chain.
 //		- return 			 resolved options or error.
 func (c *CompositeConnectionResolver) Resolve(correlationId string) (options *config.ConfigParams, err error) {
+	return c.ResolveWithContext(context.Background(), correlationId)
+}
+
+// ResolveWithContext resolves connection options like Resolve, passing the context
+// to discovery services and credential stores to support cancellation and deadlines.
+//		- ctx               context.Context
+//		- correlationId     (optional) transaction id to trace execution through call chain.
+//		- return 			 resolved options or error.
+func (c *CompositeConnectionResolver) ResolveWithContext(ctx context.Context, correlationId string) (options *config.ConfigParams, err error) {
 	var connections []*ConnectionParams
 	var credential *auth.CredentialParams
 
-	connections, err = c.ConnectionResolver.ResolveAll(correlationId)
+	connections, err = c.ConnectionResolver.ResolveAllWithContext(ctx, correlationId)
 
 	// Validate if cluster (multiple connections) is supported
 	if err == nil && len(connections) > 0 && !c.ClusterSupported {
@@ -133,7 +210,7 @@ func (c *CompositeConnectionResolver) Resolve(correlationId string) (options *co
 		return nil, err
 	}
 
-	credential, err = c.CredentialResolver.Lookup(context.Background(), correlationId)
+	credential, err = c.CredentialResolver.Lookup(ctx, correlationId)
 	if credential == nil {
 		credential = auth.NewEmptyCredentialParams()
 	}
@@ -149,6 +226,19 @@ func (c *CompositeConnectionResolver) Resolve(correlationId string) (options *co
 	return c.ComposeOptions(connections, credential, c.Options), nil
 }
 
+// ComposeTlsConfig composes TLS configuration from resolved connection options.
+//	see ConnectionUtils.ComposeTlsConfig
+//	Parameters:
+//		- correlationId (optional) transaction id to trace execution through call chain.
+//		- options connection options returned by Resolve or Compose
+//	Returns: TLS configuration or nil when TLS is not enabled, or error if PEM files are invalid.
+func (c *CompositeConnectionResolver) ComposeTlsConfig(correlationId string, options *config.ConfigParams) (*tls.Config, error) {
+	if options == nil {
+		return nil, nil
+	}
+	return ConnectionUtils.ComposeTlsConfig(correlationId, NewConnectionParams(options.Value()), nil)
+}
+
 // Compose Composite connection options from connection and credential parameters.
 //	Parameters:
 //		- correlationId (optional) transaction id to trace execution through call chain.
@@ -182,6 +272,7 @@ func (c *CompositeConnectionResolver) Compose(correlationId string, connections
 }
 
 // ValidateConnection validates connection parameters.
+// When profiles are used connections are validated by their profiles.
 // This method can be override in child classes.
 //	Parameters:
 //		- correlationId (optional) transaction id to trace execution through call chain.
@@ -192,6 +283,15 @@ func (c *CompositeConnectionResolver) ValidateConnection(correlationId string, c
 		return cerr.NewConfigError(correlationId, "NO_CONNECTION", "Connection parameters are not set is not set")
 	}
 
+	// Profiles perform complete validation including URIs
+	if len(c.Profiles) > 0 {
+		if profile := c.FindProfile(connection); profile != nil {
+			return profile.Validate(correlationId, connection)
+		}
+		protocol := connection.ProtocolWithDefault(c.DefaultProtocol)
+		return cerr.NewConfigError(correlationId, "UNSUPPORTED_PROTOCOL", "The protocol "+protocol+" is not supported")
+	}
+
 	// URI usually contains all information
 	uri := connection.Uri()
 	if uri != "" {
@@ -258,12 +358,16 @@ func (c *CompositeConnectionResolver) ComposeOptions(connections []*ConnectionPa
 }
 
 // MergeConnection merges connection options with connection parameters
+// normalized by the connection profile when profiles are used.
 // This method can be override in child classes.
 //	Parameters:
 //		-  options connection options
 //		-  connection parameters to be merged
 //	Returns: merged connection options.
 func (c *CompositeConnectionResolver) MergeConnection(options *config.ConfigParams, connection *ConnectionParams) *config.ConfigParams {
+	if profile := c.FindProfile(connection); profile != nil {
+		connection = profile.Normalize(connection)
+	}
 	var mergedOptions = options.SetDefaults(connection.ConfigParams)
 	return mergedOptions
 }
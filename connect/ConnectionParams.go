@@ -1,6 +1,9 @@
 package connect
 
 import (
+	"sort"
+	"strconv"
+
 	"github.com/pip-services3-gox/pip-services3-commons-gox/config"
 )
 
@@ -13,6 +16,14 @@ import (
 //		- host: host name or IP address
 //		- port: port number
 //		- uri: resource URI or connection string with all parameters in it
+//		- weight: relative weight of the connection for weighted selection (default: 1)
+//		- priority: priority of the connection for failover selection, lower values are preferred (default: 0)
+//		- ssl: true to use TLS connection, "tls" is an alternative key (default: false)
+//		- ssl_ca_file: path to PEM file with CA certificates to verify the server
+//		- ssl_cert_file: path to PEM file with client certificate
+//		- ssl_key_file: path to PEM file with client private key
+//		- ssl_server_name: server name to verify the server certificate
+//		- ssl_insecure: true to skip verification of the server certificate (default: false)
 //
 // In addition to standard parameters ConnectionParams may contain any number of custom parameters
 //	see ConfigParams
@@ -43,6 +54,15 @@ const (
 	ConnectionParamPort         = "port"
 	ConnectionParamURI          = "uri"
 	ConnectionParamCluster      = "cluster"
+	ConnectionParamWeight       = "weight"
+	ConnectionParamPriority     = "priority"
+	ConnectionParamSsl          = "ssl"
+	ConnectionParamTls          = "tls"
+	ConnectionParamSslCaFile    = "ssl_ca_file"
+	ConnectionParamSslCertFile  = "ssl_cert_file"
+	ConnectionParamSslKeyFile   = "ssl_key_file"
+	ConnectionParamSslServer    = "ssl_server_name"
+	ConnectionParamSslInsecure  = "ssl_insecure"
 )
 
 // NewEmptyConnectionParams creates a new connection parameters and fills it with values.
@@ -132,7 +152,17 @@ func NewManyConnectionParamsFromConfig(config *config.ConfigParams) []*Connectio
 	connections := config.GetSection(SectionNameConnections)
 
 	if connections.Len() > 0 {
-		for _, section := range connections.GetSectionNames() {
+		// Sections are sorted to keep the configured order of connections
+		names := connections.GetSectionNames()
+		sort.Slice(names, func(i, j int) bool {
+			a, errA := strconv.Atoi(names[i])
+			b, errB := strconv.Atoi(names[j])
+			if errA == nil && errB == nil {
+				return a < b
+			}
+			return names[i] < names[j]
+		})
+		for _, section := range names {
 			connection := connections.GetSection(section)
 			result = append(result, NewConnectionParams(connection.Value()))
 		}
@@ -253,3 +283,124 @@ func (c *ConnectionParams) Uri() string {
 func (c *ConnectionParams) SetUri(value string) {
 	c.Put(ConnectionParamURI, value)
 }
+
+// Weight gets the relative weight of the connection used by weighted selection.
+// Connections with zero weight are selected only when no healthy connection has a positive weight.
+//	see ConnectionResolver
+//	Returns: int the connection weight or 1 if it's not set.
+func (c *ConnectionParams) Weight() int {
+	return c.GetAsIntegerWithDefault(ConnectionParamWeight, 1)
+}
+
+// SetWeight sets the relative weight of the connection used by weighted selection.
+//	Parameters:
+//		- value int a new connection weight.
+func (c *ConnectionParams) SetWeight(value int) {
+	c.Put(ConnectionParamWeight, value)
+}
+
+// Priority gets the priority of the connection used by failover selection.
+// Connections with lower values are preferred.
+//	see ConnectionResolver
+//	Returns: int the connection priority or 0 if it's not set.
+func (c *ConnectionParams) Priority() int {
+	return c.GetAsIntegerWithDefault(ConnectionParamPriority, 0)
+}
+
+// SetPriority sets the priority of the connection used by failover selection.
+//	Parameters:
+//		- value int a new connection priority.
+func (c *ConnectionParams) SetPriority(value int) {
+	c.Put(ConnectionParamPriority, value)
+}
+
+// Ssl checks if the connection shall use TLS. The value can be stored in parameters "ssl" or "tls".
+// When neither of them is set, TLS is enabled if any of CA, certificate or key files is set.
+//	see ComposeTlsConfig
+//	Returns: bool true if TLS is enabled.
+func (c *ConnectionParams) Ssl() bool {
+	if ssl, ok := c.sslFlag(); ok {
+		return ssl
+	}
+	return c.SslCaFile() != "" || c.SslCertFile() != "" || c.SslKeyFile() != ""
+}
+
+// sslFlag gets TLS flag explicitly set in "ssl" or "tls" parameters.
+func (c *ConnectionParams) sslFlag() (bool, bool) {
+	if ssl, ok := c.GetAsNullableBoolean(ConnectionParamSsl); ok {
+		return ssl, true
+	}
+	return c.GetAsNullableBoolean(ConnectionParamTls)
+}
+
+// SetSsl enables or disables TLS.
+//	Parameters:
+//		- value bool true to use TLS connection.
+func (c *ConnectionParams) SetSsl(value bool) {
+	c.Put(ConnectionParamSsl, value)
+}
+
+// SslCaFile gets the path to PEM file with CA certificates to verify the server.
+//	Returns: string the path to CA file.
+func (c *ConnectionParams) SslCaFile() string {
+	return c.GetAsString(ConnectionParamSslCaFile)
+}
+
+// SetSslCaFile sets the path to PEM file with CA certificates to verify the server.
+//	Parameters:
+//		- value string a new path to CA file.
+func (c *ConnectionParams) SetSslCaFile(value string) {
+	c.Put(ConnectionParamSslCaFile, value)
+}
+
+// SslCertFile gets the path to PEM file with client certificate.
+//	Returns: string the path to client certificate file.
+func (c *ConnectionParams) SslCertFile() string {
+	return c.GetAsString(ConnectionParamSslCertFile)
+}
+
+// SetSslCertFile sets the path to PEM file with client certificate.
+//	Parameters:
+//		- value string a new path to client certificate file.
+func (c *ConnectionParams) SetSslCertFile(value string) {
+	c.Put(ConnectionParamSslCertFile, value)
+}
+
+// SslKeyFile gets the path to PEM file with client private key.
+//	Returns: string the path to client key file.
+func (c *ConnectionParams) SslKeyFile() string {
+	return c.GetAsString(ConnectionParamSslKeyFile)
+}
+
+// SetSslKeyFile sets the path to PEM file with client private key.
+//	Parameters:
+//		- value string a new path to client key file.
+func (c *ConnectionParams) SetSslKeyFile(value string) {
+	c.Put(ConnectionParamSslKeyFile, value)
+}
+
+// SslServerName gets the server name to verify the server certificate.
+//	Returns: string the server name.
+func (c *ConnectionParams) SslServerName() string {
+	return c.GetAsString(ConnectionParamSslServer)
+}
+
+// SetSslServerName sets the server name to verify the server certificate.
+//	Parameters:
+//		- value string a new server name.
+func (c *ConnectionParams) SetSslServerName(value string) {
+	c.Put(ConnectionParamSslServer, value)
+}
+
+// SslInsecure checks if verification of the server certificate shall be skipped.
+//	Returns: bool true to skip verification.
+func (c *ConnectionParams) SslInsecure() bool {
+	return c.GetAsBoolean(ConnectionParamSslInsecure)
+}
+
+// SetSslInsecure enables or disables verification of the server certificate.
+//	Parameters:
+//		- value bool true to skip verification.
+func (c *ConnectionParams) SetSslInsecure(value bool) {
+	c.Put(ConnectionParamSslInsecure, value)
+}
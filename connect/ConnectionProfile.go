@@ -0,0 +1,293 @@
+package connect
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	cerr "github.com/pip-services3-gox/pip-services3-commons-gox/errors"
+)
+
+// ConnectionProfile describes connections of a common protocol: its default port,
+// aliases and options allowed in connection parameters and URIs.
+// Client components can use profiles to validate and normalize connections
+// instead of implementing the same checks in ICompositeConnectionResolverOverrides.
+//	see ConnectionProfiles
+//	see CompositeConnectionResolver.UseProfiles
+//	Example:
+//		profile := ConnectionProfiles.Get("postgresql")
+//		connection := NewConnectionParamsFromTuples("uri", "POSTGRESQL://localhost/mydb?sslmode=require")
+//		err := profile.Validate("123", connection)                  // Result: nil
+//		connection = profile.Normalize(connection)
+//		uri := connection.Uri()                                     // Result: "postgres://localhost:5432/mydb?sslmode=require"
+type ConnectionProfile struct {
+	// The canonical protocol name
+	Protocol string
+
+	// Other names of the protocol that are normalized into the canonical name
+	Aliases []string
+
+	// The default port number
+	DefaultPort int
+
+	// The options allowed in connection parameters and URIs or nil to allow any options
+	AllowedOptions []string
+}
+
+// connectionStandardKeys are connection parameters allowed in every profile
+var connectionStandardKeys = []string{
+	ConnectionParamDiscoveryKey, ConnectionParamProtocol, ConnectionParamHost, ConnectionParamIp,
+	ConnectionParamPort, ConnectionParamURI, ConnectionParamCluster, ConnectionParamWeight,
+	ConnectionParamPriority, ConnectionParamSsl, ConnectionParamTls, ConnectionParamSslCaFile,
+	ConnectionParamSslCertFile, ConnectionParamSslKeyFile, ConnectionParamSslServer, ConnectionParamSslInsecure,
+	"servers", "path", "username", "password",
+}
+
+// NewConnectionProfile creates a new connection profile.
+//	Parameters:
+//		- protocol string a canonical protocol name.
+//		- defaultPort int a default port number.
+//		- allowedOptions []string options allowed in connections or nil to allow any options.
+//		- aliases ...string other names of the protocol.
+//	Returns: *ConnectionProfile
+func NewConnectionProfile(protocol string, defaultPort int, allowedOptions []string, aliases ...string) *ConnectionProfile {
+	return &ConnectionProfile{
+		Protocol:       strings.ToLower(protocol),
+		Aliases:        aliases,
+		DefaultPort:    defaultPort,
+		AllowedOptions: allowedOptions,
+	}
+}
+
+// Matches checks if the protocol is the profile protocol or one of its aliases.
+//	Parameters:
+//		- protocol string a protocol name to check.
+//	Returns: bool true if the protocol matches the profile.
+func (c *ConnectionProfile) Matches(protocol string) bool {
+	protocol = strings.ToLower(protocol)
+	if protocol == c.Protocol {
+		return true
+	}
+	for _, alias := range c.Aliases {
+		if protocol == strings.ToLower(alias) {
+			return true
+		}
+	}
+	return false
+}
+
+// IsOptionAllowed checks if the option is allowed in connections of this profile.
+//	Parameters:
+//		- key string an option key.
+//	Returns: bool true if the option is allowed.
+func (c *ConnectionProfile) IsOptionAllowed(key string) bool {
+	if c.AllowedOptions == nil || indexOf(connectionStandardKeys, key) >= 0 {
+		return true
+	}
+	for _, option := range c.AllowedOptions {
+		if strings.EqualFold(option, key) {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks that connection parameters match the profile: the protocol is supported,
+// the host and port are set and valid, and only allowed options are used.
+// Connections defined by URIs are validated by parsing their URIs.
+//	Parameters:
+//		- correlationId string (optional) transaction id to trace execution through call chain.
+//		- connection *ConnectionParams parameters to be validated.
+//	Returns: error or nil if validation was successful.
+func (c *ConnectionProfile) Validate(correlationId string, connection *ConnectionParams) error {
+	if connection == nil {
+		return cerr.NewConfigError(correlationId, "NO_CONNECTION", "Connection parameters are not set")
+	}
+
+	options := connection.ConfigParams
+	if uri := connection.Uri(); uri != "" {
+		options = ConnectionUtils.ParseUri(uri, c.Protocol, c.DefaultPort)
+		for _, key := range connection.Keys() {
+			if key != ConnectionParamURI {
+				options.SetAsObject(key, connection.GetAsString(key))
+			}
+		}
+	}
+
+	protocol := options.GetAsStringWithDefault(ConnectionParamProtocol, c.Protocol)
+	if !c.Matches(protocol) {
+		return cerr.NewConfigError(
+			correlationId, "UNSUPPORTED_PROTOCOL", "The protocol "+protocol+" is not supported",
+		).WithDetails("protocol", protocol)
+	}
+
+	host := options.GetAsString(ConnectionParamHost)
+	if host == "" {
+		host = options.GetAsString(ConnectionParamIp)
+	}
+	if host == "" {
+		return cerr.NewConfigError(correlationId, "NO_HOST", "Connection host is not set")
+	}
+
+	defaultPort := strconv.Itoa(c.DefaultPort)
+	for _, port := range strings.Split(options.GetAsStringWithDefault(ConnectionParamPort, defaultPort), ",") {
+		if port == "" {
+			port = defaultPort
+		}
+		value, err := strconv.Atoi(port)
+		if err != nil || value < 0 || value > 65535 {
+			return cerr.NewConfigError(
+				correlationId, "INVALID_PORT", "Connection port "+port+" is invalid",
+			).WithDetails("port", port)
+		}
+		if value == 0 {
+			return cerr.NewConfigError(correlationId, "NO_PORT", "Connection port is not set")
+		}
+	}
+
+	for _, key := range options.Keys() {
+		if !c.IsOptionAllowed(key) {
+			return cerr.NewConfigError(
+				correlationId, "UNSUPPORTED_OPTION", "The option "+key+" is not supported by "+c.Protocol+" protocol",
+			).WithDetails("option", key).WithDetails("protocol", c.Protocol)
+		}
+	}
+
+	return nil
+}
+
+// Normalize converts connection parameters into the canonical form: protocol aliases are replaced
+// with the canonical protocol name, default ports are set and URIs are recomposed with
+// percent-encoded elements and sorted parameters.
+// The connection is not changed and normalized copy is returned.
+//	Parameters:
+//		- connection *ConnectionParams parameters to be normalized.
+//	Returns: *ConnectionParams normalized connection parameters.
+func (c *ConnectionProfile) Normalize(connection *ConnectionParams) *ConnectionParams {
+	if connection == nil {
+		return nil
+	}
+
+	result := NewConnectionParams(connection.Value())
+
+	if protocol := result.Protocol(); protocol == "" || c.Matches(protocol) {
+		result.SetProtocol(c.Protocol)
+	}
+
+	if uri := result.Uri(); uri != "" {
+		options := ConnectionUtils.ParseUri(uri, c.Protocol, c.DefaultPort)
+		if protocol := options.GetAsString(ConnectionParamProtocol); c.Matches(protocol) {
+			options.SetAsObject(ConnectionParamProtocol, c.Protocol)
+		}
+		result.SetUri(ConnectionUtils.ComposeUri(options, c.Protocol, c.DefaultPort))
+		if _, ok := connection.Get(ConnectionParamHost); !ok {
+			result.SetAsObject(ConnectionParamHost, options.GetAsString(ConnectionParamHost))
+			result.SetAsObject(ConnectionParamPort, options.GetAsString(ConnectionParamPort))
+		}
+		result.SetProtocol(options.GetAsString(ConnectionParamProtocol))
+		return result
+	}
+
+	if result.Port() == 0 && c.DefaultPort > 0 {
+		result.SetPort(c.DefaultPort)
+	}
+	return result
+}
+
+// ConnectionProfiles is a registry of connection profiles for common protocols.
+// It contains http, https, grpc, mongodb, postgres, redis, amqp, nats and kafka profiles,
+// and custom profiles can be added using Register method.
+var ConnectionProfiles = newConnectionProfiles()
+
+// _TConnectionProfiles a thread-safe registry of connection profiles
+type _TConnectionProfiles struct {
+	profiles map[string]*ConnectionProfile
+	mtx      sync.RWMutex
+}
+
+func newConnectionProfiles() *_TConnectionProfiles {
+	httpOptions := []string{"timeout", "connect_timeout", "retries", "max_connections", "max_idle_connections"}
+	c := &_TConnectionProfiles{
+		profiles: map[string]*ConnectionProfile{},
+	}
+
+	c.Register(NewConnectionProfile("http", 80, httpOptions))
+	c.Register(NewConnectionProfile("https", 443, httpOptions))
+	c.Register(NewConnectionProfile("grpc", 50051, []string{
+		"timeout", "connect_timeout", "retries", "max_message_size", "keepalive_time", "keepalive_timeout",
+	}))
+	c.Register(NewConnectionProfile("mongodb", 27017, []string{
+		"authSource", "authMechanism", "replicaSet", "ssl", "tls", "retryWrites", "retryReads", "w",
+		"journal", "readPreference", "maxPoolSize", "minPoolSize", "maxIdleTimeMS", "connectTimeoutMS",
+		"socketTimeoutMS", "serverSelectionTimeoutMS", "appName", "compressors", "directConnection",
+	}))
+	c.Register(NewConnectionProfile("postgres", 5432, []string{
+		"sslmode", "sslrootcert", "sslcert", "sslkey", "connect_timeout", "application_name",
+		"search_path", "target_session_attrs", "pool_max_conns", "pool_min_conns", "statement_timeout",
+	}, "postgresql"))
+	c.Register(NewConnectionProfile("redis", 6379, []string{
+		"db", "timeout", "connect_timeout", "read_timeout", "write_timeout", "retries",
+		"pool_size", "min_idle_connections", "client_name",
+	}))
+	c.Register(NewConnectionProfile("amqp", 5672, []string{
+		"heartbeat", "connection_timeout", "channel_max", "frame_max", "locale",
+	}))
+	c.Register(NewConnectionProfile("nats", 4222, []string{
+		"name", "timeout", "connect_timeout", "reconnect", "max_reconnect", "reconnect_wait",
+		"ping_interval", "max_pings_out", "verbose", "pedantic",
+	}))
+	c.Register(NewConnectionProfile("kafka", 9092, []string{
+		"client_id", "acks", "connect_timeout", "timeout", "retries", "retry_timeout", "batch_size",
+		"compression", "num_partitions", "replication_factor", "sasl_mechanism",
+	}))
+
+	return c
+}
+
+// Register adds a connection profile or replaces a profile with the same protocol.
+//	Parameters:
+//		- profile *ConnectionProfile a profile to be added.
+func (c *_TConnectionProfiles) Register(profile *ConnectionProfile) {
+	if profile == nil {
+		return
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.profiles[strings.ToLower(profile.Protocol)] = profile
+}
+
+// Get finds a connection profile by its protocol or an alias.
+//	Parameters:
+//		- protocol string a protocol name or alias.
+//	Returns: *ConnectionProfile a found profile or nil if the protocol is unknown.
+func (c *_TConnectionProfiles) Get(protocol string) *ConnectionProfile {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	if profile, ok := c.profiles[strings.ToLower(protocol)]; ok {
+		return profile
+	}
+	for _, profile := range c.profiles {
+		if profile.Matches(protocol) {
+			return profile
+		}
+	}
+	return nil
+}
+
+// Protocols gets canonical protocols of all registered profiles.
+//	Returns: []string a sorted list of protocols.
+func (c *_TConnectionProfiles) Protocols() []string {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	result := make([]string, 0, len(c.profiles))
+	for protocol := range c.profiles {
+		result = append(result, protocol)
+	}
+	sort.Strings(result)
+	return result
+}
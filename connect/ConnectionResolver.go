@@ -2,8 +2,11 @@ package connect
 
 import (
 	"context"
+	"sync"
+	"time"
 
 	"github.com/pip-services3-gox/pip-services3-commons-gox/config"
+	cerr "github.com/pip-services3-gox/pip-services3-commons-gox/errors"
 	"github.com/pip-services3-gox/pip-services3-commons-gox/refer"
 )
 
@@ -20,8 +23,12 @@ import (
 //				- ... connection parameters for key 1
 //			- [connection params N]: Nth connection parameters
 //				- ... connection parameters for key N
+//		- options:
+//			- connection_strategy: strategy to select one of multiple connections:
+//			  first, round_robin, random, weighted or priority (default: first)
+//			- connection_cooldown: time in milliseconds to skip a connection marked unhealthy (default: 30000)
 //	References:
-//		- *:discovery:*:*:1.0 (optional) IDiscovery services to resolve connections
+//		- *:discovery:*:*:1.0 (optional) IContextDiscovery or IDiscovery services to resolve connections
 //
 //	see ConnectionParams
 //	see IDiscovery
@@ -36,11 +43,23 @@ import (
 //		connectionResolver.SetReferences(context.Background(), references);
 //		res, err := connectionResolver.Resolve("123");
 //
+//		// When the connection fails, skip it during the cooldown period
+//		connectionResolver.MarkUnhealthy(res);
+//
 type ConnectionResolver struct {
-	connections []*ConnectionParams
-	references  refer.IReferences
+	connections  []*ConnectionParams
+	references   refer.IReferences
+	selector     *connectionSelector
+	selectorOnce sync.Once
 }
 
+const (
+	// ConnectionResolverStrategyKey is a constant for connection selection strategy key
+	ConnectionResolverStrategyKey = "options.connection_strategy"
+	// ConnectionResolverCooldownKey is a constant for unhealthy connection cooldown key
+	ConnectionResolverCooldownKey = "options.connection_cooldown"
+)
+
 // NewEmptyConnectionResolver creates a new instance of connection resolver.
 //	Returns: *ConnectionResolver
 func NewEmptyConnectionResolver() *ConnectionResolver {
@@ -70,12 +89,116 @@ func NewConnectionResolver(ctx context.Context, config *config.ConfigParams, ref
 }
 
 // Configure Configures component by passing configuration parameters.
+// Unknown connection strategies are reported by Resolve as configuration errors.
 //	Parameters:
 //		- ctx context.Context
 //		- config *config.ConfigParams configuration parameters to be set.
 func (c *ConnectionResolver) Configure(ctx context.Context, config *config.ConfigParams) {
 	connections := NewManyConnectionParamsFromConfig(config)
 	c.connections = append(c.connections, connections...)
+
+	selector := c.getSelector()
+	selector.mtx.Lock()
+	defer selector.mtx.Unlock()
+
+	selector.strategy = config.GetAsStringWithDefault(ConnectionResolverStrategyKey, selector.strategy)
+	cooldown := config.GetAsLongWithDefault(ConnectionResolverCooldownKey, selector.cooldown.Milliseconds())
+	selector.cooldown = time.Duration(cooldown) * time.Millisecond
+}
+
+// getSelector gets the connection selector creating it on first use,
+// so zero-value resolvers are ready to use.
+func (c *ConnectionResolver) getSelector() *connectionSelector {
+	c.selectorOnce.Do(func() {
+		if c.selector == nil {
+			c.selector = newConnectionSelector()
+		}
+	})
+	return c.selector
+}
+
+// Strategy gets the strategy to select one of multiple connections.
+//	Returns: string the connection selection strategy.
+func (c *ConnectionResolver) Strategy() string {
+	selector := c.getSelector()
+	selector.mtx.Lock()
+	defer selector.mtx.Unlock()
+
+	return selector.strategy
+}
+
+// SetStrategy sets the strategy to select one of multiple connections.
+//	see ConnectionStrategyFirst
+//	see ConnectionStrategyRoundRobin
+//	see ConnectionStrategyRandom
+//	see ConnectionStrategyWeighted
+//	see ConnectionStrategyPriority
+//	Parameters:
+//		- strategy string a new connection selection strategy.
+func (c *ConnectionResolver) SetStrategy(strategy string) {
+	selector := c.getSelector()
+	selector.mtx.Lock()
+	defer selector.mtx.Unlock()
+
+	selector.strategy = strategy
+}
+
+// Cooldown gets the time to skip connections marked unhealthy.
+//	Returns: time.Duration the cooldown period.
+func (c *ConnectionResolver) Cooldown() time.Duration {
+	selector := c.getSelector()
+	selector.mtx.Lock()
+	defer selector.mtx.Unlock()
+
+	return selector.cooldown
+}
+
+// SetCooldown sets the time to skip connections marked unhealthy.
+//	Parameters:
+//		- cooldown time.Duration a new cooldown period.
+func (c *ConnectionResolver) SetCooldown(cooldown time.Duration) {
+	selector := c.getSelector()
+	selector.mtx.Lock()
+	defer selector.mtx.Unlock()
+
+	selector.cooldown = cooldown
+}
+
+// MarkUnhealthy marks the connection unhealthy, so it is skipped by Resolve
+// during the configured cooldown period unless all connections are unhealthy.
+//	Parameters:
+//		- connection *ConnectionParams a connection that failed.
+func (c *ConnectionResolver) MarkUnhealthy(connection *ConnectionParams) {
+	if connection != nil {
+		c.getSelector().markUnhealthy(connection, -1)
+	}
+}
+
+// MarkUnhealthyFor marks the connection unhealthy for the given cooldown period.
+//	Parameters:
+//		- connection *ConnectionParams a connection that failed.
+//		- cooldown time.Duration a period to skip the connection.
+func (c *ConnectionResolver) MarkUnhealthyFor(connection *ConnectionParams, cooldown time.Duration) {
+	if connection != nil {
+		c.getSelector().markUnhealthy(connection, cooldown)
+	}
+}
+
+// MarkHealthy removes the unhealthy mark from the connection before its cooldown ends.
+//	Parameters:
+//		- connection *ConnectionParams a connection that recovered.
+func (c *ConnectionResolver) MarkHealthy(connection *ConnectionParams) {
+	if connection != nil {
+		c.getSelector().markHealthy(connection)
+	}
+}
+
+// IsHealthy checks if the connection is not marked unhealthy.
+//	Parameters:
+//		- connection *ConnectionParams a connection to check.
+//	Returns: bool true if the connection is healthy.
+func (c *ConnectionResolver) IsHealthy(connection *ConnectionParams) bool {
+	return connection != nil && c.getSelector().isHealthy(connection)
 }
 
 // SetReferences sets references to dependent components.
@@ -101,7 +224,7 @@ func (c *ConnectionResolver) Add(connection *ConnectionParams) {
 	c.connections = append(c.connections, connection)
 }
 
-func (c *ConnectionResolver) resolveInDiscovery(correlationId string,
+func (c *ConnectionResolver) resolveInDiscovery(ctx context.Context, correlationId string,
 	connection *ConnectionParams) (result *ConnectionParams, err error) {
 
 	if !connection.UseDiscovery() {
@@ -121,8 +244,11 @@ func (c *ConnectionResolver) resolveInDiscovery(correlationId string,
 	}
 
 	for _, component := range components {
-		if discovery, ok := component.(IDiscovery); ok && discovery != nil {
-			connection, err = discovery.ResolveOne(correlationId, key)
+		if discovery, ok := toDiscovery(component); ok {
+			if err = ctx.Err(); err != nil {
+				return nil, err
+			}
+			connection, err = discovery.ResolveOne(ctx, correlationId, key)
 			if connection != nil || err != nil {
 				return connection, err
 			}
@@ -134,15 +260,45 @@ func (c *ConnectionResolver) resolveInDiscovery(correlationId string,
 
 // Resolve a single component connection. If connections are configured to be retrieved
 // from Discovery service it finds a IDiscovery and resolves the connection there.
+// When there are multiple connections, one of them is selected by the configured strategy
+// among connections that are not marked unhealthy.
+// Unknown strategies are reported as configuration errors.
 //	see IDiscovery
 //	Parameters:
 //		- correlationId: string transaction id to trace execution through call chain.
 //	Returns: *ConnectionParams, error resolved connection or error.
 func (c *ConnectionResolver) Resolve(correlationId string) (*ConnectionParams, error) {
+	return c.ResolveWithContext(context.Background(), correlationId)
+}
+
+// ResolveWithContext resolves a single component connection like Resolve,
+// passing the context to discovery services to support cancellation and deadlines.
+//	see Resolve
+//	Parameters:
+//		- ctx context.Context
+//		- correlationId: string transaction id to trace execution through call chain.
+//	Returns: *ConnectionParams, error resolved connection or error.
+func (c *ConnectionResolver) ResolveWithContext(ctx context.Context, correlationId string) (*ConnectionParams, error) {
 	if len(c.connections) == 0 {
 		return nil, nil
 	}
 
+	strategy := c.Strategy()
+	if !isConnectionStrategy(strategy) {
+		return nil, cerr.NewConfigError(
+			correlationId, "INVALID_CONNECTION_STRATEGY", "Unknown connection strategy "+strategy,
+		).WithDetails("strategy", strategy)
+	}
+
+	selector := c.getSelector()
+	if strategy != ConnectionStrategyFirst || selector.hasUnhealthy() {
+		connections, err := c.ResolveAllWithContext(ctx, correlationId)
+		if err != nil {
+			return nil, err
+		}
+		return selector.selectConnection(connections), nil
+	}
+
 	resolveConnections := make([]*ConnectionParams, 0)
 
 	for _, connection := range c.connections {
@@ -154,7 +310,7 @@ func (c *ConnectionResolver) Resolve(correlationId string) (*ConnectionParams, e
 	}
 
 	for _, connection := range resolveConnections {
-		c, err := c.resolveInDiscovery(correlationId, connection)
+		c, err := c.resolveInDiscovery(ctx, correlationId, connection)
 		if c != nil || err != nil {
 			return c, err
 		}
@@ -163,7 +319,7 @@ func (c *ConnectionResolver) Resolve(correlationId string) (*ConnectionParams, e
 	return nil, nil
 }
 
-func (c *ConnectionResolver) resolveAllInDiscovery(correlationId string,
+func (c *ConnectionResolver) resolveAllInDiscovery(ctx context.Context, correlationId string,
 	connection *ConnectionParams) (result []*ConnectionParams, err error) {
 
 	if !connection.UseDiscovery() {
@@ -185,8 +341,11 @@ func (c *ConnectionResolver) resolveAllInDiscovery(correlationId string,
 	resolvedConnections := make([]*ConnectionParams, 0)
 
 	for _, component := range components {
-		if discovery, ok := component.(IDiscovery); ok && discovery != nil {
-			connections, err := discovery.ResolveAll(correlationId, key)
+		if discovery, ok := toDiscovery(component); ok {
+			if err = ctx.Err(); err != nil {
+				return nil, err
+			}
+			connections, err := discovery.ResolveAll(ctx, correlationId, key)
 			if err != nil {
 				return nil, err
 			}
@@ -208,6 +367,17 @@ func (c *ConnectionResolver) resolveAllInDiscovery(correlationId string,
 //		- correlationId string transaction id to trace execution through call chain.
 //	Returns: []*ConnectionParams, error resolved connections or error.
 func (c *ConnectionResolver) ResolveAll(correlationId string) ([]*ConnectionParams, error) {
+	return c.ResolveAllWithContext(context.Background(), correlationId)
+}
+
+// ResolveAllWithContext resolves all component connections like ResolveAll,
+// passing the context to discovery services to support cancellation and deadlines.
+//	see ResolveAll
+//	Parameters:
+//		- ctx context.Context
+//		- correlationId string transaction id to trace execution through call chain.
+//	Returns: []*ConnectionParams, error resolved connections or error.
+func (c *ConnectionResolver) ResolveAllWithContext(ctx context.Context, correlationId string) ([]*ConnectionParams, error) {
 	resolvedConnections := make([]*ConnectionParams, 0)
 	resolveConnections := make([]*ConnectionParams, 0)
 
@@ -220,7 +390,7 @@ func (c *ConnectionResolver) ResolveAll(correlationId string) ([]*ConnectionPara
 	}
 
 	for _, connection := range resolveConnections {
-		connections, err := c.resolveAllInDiscovery(correlationId, connection)
+		connections, err := c.resolveAllInDiscovery(ctx, correlationId, connection)
 		if err != nil {
 			return nil, err
 		}
@@ -230,7 +400,7 @@ func (c *ConnectionResolver) ResolveAll(correlationId string) ([]*ConnectionPara
 	return resolvedConnections, nil
 }
 
-func (c *ConnectionResolver) registerInDiscovery(correlationId string,
+func (c *ConnectionResolver) registerInDiscovery(ctx context.Context, correlationId string,
 	connection *ConnectionParams) (result bool, err error) {
 
 	if !connection.UseDiscovery() {
@@ -252,8 +422,11 @@ func (c *ConnectionResolver) registerInDiscovery(correlationId string,
 	registered := false
 
 	for _, component := range components {
-		if discovery, ok := component.(IDiscovery); ok && discovery != nil {
-			_, err = discovery.Register(correlationId, key, connection)
+		if discovery, ok := toDiscovery(component); ok {
+			if err = ctx.Err(); err != nil {
+				return false, err
+			}
+			_, err = discovery.Register(ctx, correlationId, key, connection)
 			if err != nil {
 				return false, err
 			}
@@ -272,9 +445,90 @@ func (c *ConnectionResolver) registerInDiscovery(correlationId string,
 //		- connection *ConnectionParams a connection to register.
 //	Returns: error
 func (c *ConnectionResolver) Register(correlationId string, connection *ConnectionParams) error {
-	registered, err := c.registerInDiscovery(correlationId, connection)
+	return c.RegisterWithContext(context.Background(), correlationId, connection)
+}
+
+// RegisterWithContext registers the given connection in all referenced discovery services like Register,
+// passing the context to discovery services to support cancellation and deadlines.
+//	see Register
+//	Parameters:
+//		- ctx context.Context
+//		- correlationId string transaction id to trace execution through call chain.
+//		- connection *ConnectionParams a connection to register.
+//	Returns: error
+func (c *ConnectionResolver) RegisterWithContext(ctx context.Context, correlationId string, connection *ConnectionParams) error {
+	registered, err := c.registerInDiscovery(ctx, correlationId, connection)
 	if registered {
 		c.connections = append(c.connections, connection)
 	}
 	return err
 }
+
+func (c *ConnectionResolver) unregisterInDiscovery(ctx context.Context, correlationId string,
+	connection *ConnectionParams) (result bool, err error) {
+
+	if !connection.UseDiscovery() {
+		return false, nil
+	}
+
+	key := connection.DiscoveryKey()
+	if c.references == nil {
+		return false, nil
+	}
+
+	discoveryDescriptor := refer.NewDescriptor("*", "discovery", "*", "*", "*")
+	components := c.references.GetOptional(discoveryDescriptor)
+	if len(components) == 0 {
+		err := refer.NewReferenceError(correlationId, discoveryDescriptor)
+		return false, err
+	}
+
+	unregistered := false
+
+	for _, component := range components {
+		if discovery, ok := toDiscovery(component); ok {
+			if err = ctx.Err(); err != nil {
+				return false, err
+			}
+			err = discovery.Unregister(ctx, correlationId, key, connection)
+			if err != nil {
+				return false, err
+			}
+			unregistered = true
+		}
+	}
+
+	return unregistered, nil
+}
+
+// Unregister the given connection from all referenced discovery services.
+// This method is used when a service shuts down to stop clients from connecting to it.
+//	see IDiscovery
+//	Parameters:
+//		- correlationId string transaction id to trace execution through call chain.
+//		- connection *ConnectionParams a previously registered connection.
+//	Returns: error
+func (c *ConnectionResolver) Unregister(correlationId string, connection *ConnectionParams) error {
+	return c.UnregisterWithContext(context.Background(), correlationId, connection)
+}
+
+// UnregisterWithContext unregisters the given connection from all referenced discovery services like Unregister,
+// passing the context to discovery services to support cancellation and deadlines.
+//	see Unregister
+//	Parameters:
+//		- ctx context.Context
+//		- correlationId string transaction id to trace execution through call chain.
+//		- connection *ConnectionParams a previously registered connection.
+//	Returns: error
+func (c *ConnectionResolver) UnregisterWithContext(ctx context.Context, correlationId string, connection *ConnectionParams) error {
+	unregistered, err := c.unregisterInDiscovery(ctx, correlationId, connection)
+	if unregistered {
+		for i, item := range c.connections {
+			if item == connection {
+				c.connections = append(c.connections[:i], c.connections[i+1:]...)
+				break
+			}
+		}
+	}
+	return err
+}
@@ -0,0 +1,185 @@
+package connect
+
+import (
+	"math/rand"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Strategies to select a connection from multiple resolved connections.
+const (
+	// ConnectionStrategyFirst selects the first healthy connection
+	ConnectionStrategyFirst = "first"
+	// ConnectionStrategyRoundRobin selects healthy connections in turn
+	ConnectionStrategyRoundRobin = "round_robin"
+	// ConnectionStrategyRandom selects a random healthy connection
+	ConnectionStrategyRandom = "random"
+	// ConnectionStrategyWeighted selects a random healthy connection proportionally to its weight.
+	// Connections with zero weight are used only when all healthy connections have zero weight,
+	// in which case they are selected uniformly
+	ConnectionStrategyWeighted = "weighted"
+	// ConnectionStrategyPriority selects the first healthy connection with the lowest priority value,
+	// so lower priority connections are used only when preferred ones are marked unhealthy
+	ConnectionStrategyPriority = "priority"
+)
+
+// isConnectionStrategy checks if the strategy is one of the supported connection selection strategies.
+func isConnectionStrategy(strategy string) bool {
+	switch strategy {
+	case ConnectionStrategyFirst, ConnectionStrategyRoundRobin, ConnectionStrategyRandom,
+		ConnectionStrategyWeighted, ConnectionStrategyPriority:
+		return true
+	}
+	return false
+}
+
+// connectionSelector selects connections using a strategy and tracks unhealthy connections.
+type connectionSelector struct {
+	strategy  string
+	cooldown  time.Duration
+	counter   uint64
+	unhealthy map[string]time.Time
+	random    *rand.Rand
+	mtx       sync.Mutex
+}
+
+func newConnectionSelector() *connectionSelector {
+	return &connectionSelector{
+		strategy:  ConnectionStrategyFirst,
+		cooldown:  30 * time.Second,
+		unhealthy: make(map[string]time.Time),
+		random:    rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// connectionHealthKey identifies a connection to track its health.
+func connectionHealthKey(connection *ConnectionParams) string {
+	if uri := connection.Uri(); uri != "" {
+		return uri
+	}
+	if host := connection.Host(); host != "" {
+		return connection.Protocol() + "://" + host + ":" + strconv.Itoa(connection.Port())
+	}
+	return connection.String()
+}
+
+func (c *connectionSelector) markUnhealthy(connection *ConnectionParams, cooldown time.Duration) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if cooldown < 0 {
+		cooldown = c.cooldown
+	}
+	c.unhealthy[connectionHealthKey(connection)] = time.Now().Add(cooldown)
+}
+
+func (c *connectionSelector) markHealthy(connection *ConnectionParams) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	delete(c.unhealthy, connectionHealthKey(connection))
+}
+
+func (c *connectionSelector) isHealthy(connection *ConnectionParams) bool {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	return c.isHealthyAt(connectionHealthKey(connection), time.Now())
+}
+
+func (c *connectionSelector) isHealthyAt(key string, now time.Time) bool {
+	until, ok := c.unhealthy[key]
+	if !ok {
+		return true
+	}
+	if !now.Before(until) {
+		delete(c.unhealthy, key)
+		return true
+	}
+	return false
+}
+
+func (c *connectionSelector) hasUnhealthy() bool {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	return len(c.unhealthy) > 0
+}
+
+// selectConnection selects a connection among healthy ones.
+// When all connections are unhealthy the one that recovers first is selected.
+func (c *connectionSelector) selectConnection(connections []*ConnectionParams) *ConnectionParams {
+	if len(connections) == 0 {
+		return nil
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	now := time.Now()
+	healthy := make([]*ConnectionParams, 0, len(connections))
+	for _, connection := range connections {
+		if c.isHealthyAt(connectionHealthKey(connection), now) {
+			healthy = append(healthy, connection)
+		}
+	}
+
+	if len(healthy) == 0 {
+		result := connections[0]
+		for _, connection := range connections[1:] {
+			if c.unhealthy[connectionHealthKey(connection)].Before(c.unhealthy[connectionHealthKey(result)]) {
+				result = connection
+			}
+		}
+		return result
+	}
+
+	switch c.strategy {
+	case ConnectionStrategyRoundRobin:
+		index := c.counter % uint64(len(healthy))
+		c.counter++
+		return healthy[index]
+
+	case ConnectionStrategyRandom:
+		return healthy[c.random.Intn(len(healthy))]
+
+	case ConnectionStrategyWeighted:
+		return c.selectWeighted(healthy)
+
+	case ConnectionStrategyPriority:
+		sort.SliceStable(healthy, func(i, j int) bool {
+			return healthy[i].Priority() < healthy[j].Priority()
+		})
+		return healthy[0]
+	}
+
+	return healthy[0]
+}
+
+// selectWeighted selects a random connection proportionally to its weight.
+// When no connection has a positive weight it selects one uniformly,
+// so connections with zero weight still serve requests when they are the only healthy ones.
+func (c *connectionSelector) selectWeighted(healthy []*ConnectionParams) *ConnectionParams {
+	total := 0
+	for _, connection := range healthy {
+		if weight := connection.Weight(); weight > 0 {
+			total += weight
+		}
+	}
+	if total == 0 {
+		return healthy[c.random.Intn(len(healthy))]
+	}
+
+	value := c.random.Intn(total)
+	for _, connection := range healthy {
+		if weight := connection.Weight(); weight > 0 {
+			if value < weight {
+				return connection
+			}
+			value -= weight
+		}
+	}
+	return healthy[len(healthy)-1]
+}
@@ -0,0 +1,112 @@
+package connect
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"os"
+	"time"
+
+	cerr "github.com/pip-services3-gox/pip-services3-commons-gox/errors"
+	"github.com/pip-services3-gox/pip-services3-components-gox/auth"
+)
+
+// ComposeTlsConfig composes TLS configuration from connection and credential parameters.
+// PEM files with CA certificates, client certificate and key are loaded and validated.
+// Client certificate and key in credential parameters take precedence over connection parameters.
+// When "ssl" and "tls" parameters are not set, TLS is enabled if any of CA, certificate or key files is set,
+// so a connection configured with certificates never falls back to a plain connection.
+//	see ConnectionParams.Ssl
+//	Parameters:
+//		- correlationId string (optional) transaction id to trace execution through call chain.
+//		- connection *ConnectionParams connection parameters with TLS settings.
+//		- credential *auth.CredentialParams (optional) credential parameters with client certificate and key.
+//	Returns: *tls.Config, error TLS configuration or nil when TLS is not enabled, or error if PEM files are invalid.
+//	Example:
+//		connection := NewConnectionParamsFromTuples(
+//			"host", "localhost", "port", 6380, "ssl", true, "ssl_ca_file", "./ca.pem",
+//		)
+//		credential := auth.NewCredentialParamsFromTuples(
+//			"ssl_cert_file", "./client.pem", "ssl_key_file", "./client-key.pem",
+//		)
+//		tlsConfig, err := ConnectionUtils.ComposeTlsConfig("123", connection, credential)
+func (c *_TConnectionUtils) ComposeTlsConfig(correlationId string, connection *ConnectionParams,
+	credential *auth.CredentialParams) (*tls.Config, error) {
+
+	if connection == nil || !isTlsEnabled(connection, credential) {
+		return nil, nil
+	}
+
+	config := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         connection.SslServerName(),
+		InsecureSkipVerify: connection.SslInsecure(),
+	}
+
+	if caFile := connection.SslCaFile(); caFile != "" {
+		data, err := os.ReadFile(caFile)
+		if err != nil {
+			return nil, cerr.NewFileError(
+				correlationId, "READ_FAILED", "Failed to read CA file "+caFile,
+			).WithDetails("path", caFile).WithCause(err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(data) {
+			return nil, cerr.NewConfigError(
+				correlationId, "INVALID_CA_FILE", "CA file "+caFile+" contains no valid PEM certificates",
+			).WithDetails("path", caFile)
+		}
+		config.RootCAs = pool
+	}
+
+	certFile := connection.SslCertFile()
+	keyFile := connection.SslKeyFile()
+	if credential != nil {
+		if value := credential.SslCertFile(); value != "" {
+			certFile = value
+		}
+		if value := credential.SslKeyFile(); value != "" {
+			keyFile = value
+		}
+	}
+
+	if certFile == "" && keyFile == "" {
+		return config, nil
+	}
+	if certFile == "" || keyFile == "" {
+		return nil, cerr.NewConfigError(
+			correlationId, "INCOMPLETE_CLIENT_CERT", "Client certificate and key files must be set together",
+		).WithDetails("cert_file", certFile).WithDetails("key_file", keyFile)
+	}
+
+	certificate, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, cerr.NewConfigError(
+			correlationId, "INVALID_CLIENT_CERT", "Failed to load client certificate "+certFile+" and key "+keyFile,
+		).WithDetails("cert_file", certFile).WithDetails("key_file", keyFile).WithCause(err)
+	}
+
+	leaf, err := x509.ParseCertificate(certificate.Certificate[0])
+	if err != nil {
+		return nil, cerr.NewConfigError(
+			correlationId, "INVALID_CLIENT_CERT", "Failed to parse client certificate "+certFile,
+		).WithDetails("cert_file", certFile).WithCause(err)
+	}
+	if now := time.Now(); now.After(leaf.NotAfter) || now.Before(leaf.NotBefore) {
+		return nil, cerr.NewConfigError(
+			correlationId, "CLIENT_CERT_EXPIRED", "Client certificate "+certFile+" is not valid at this time",
+		).WithDetails("cert_file", certFile).WithDetails("not_before", leaf.NotBefore).WithDetails("not_after", leaf.NotAfter)
+	}
+
+	certificate.Leaf = leaf
+	config.Certificates = []tls.Certificate{certificate}
+	return config, nil
+}
+
+// isTlsEnabled checks if TLS is enabled explicitly or implied by configured PEM files.
+// Client certificate and key in credential parameters enable TLS like the ones in connection parameters.
+func isTlsEnabled(connection *ConnectionParams, credential *auth.CredentialParams) bool {
+	if _, ok := connection.sslFlag(); ok || connection.Ssl() {
+		return connection.Ssl()
+	}
+	return credential != nil && (credential.SslCertFile() != "" || credential.SslKeyFile() != "")
+}
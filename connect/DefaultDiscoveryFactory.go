@@ -5,15 +5,23 @@ import (
 	"github.com/pip-services3-gox/pip-services3-components-gox/build"
 )
 
-// MemoryDiscoveryDescriptor creates IDiscovery components by their descriptors.
+// MemoryDiscoveryDescriptor creates IContextDiscovery components by their descriptors.
 var MemoryDiscoveryDescriptor = refer.NewDescriptor("pip-services", "discovery", "memory", "*", "1.0")
 
+// FileDiscoveryDescriptor creates IContextDiscovery components by their descriptors.
+var FileDiscoveryDescriptor = refer.NewDescriptor("pip-services", "discovery", "file", "*", "1.0")
+
+// DnsDiscoveryDescriptor creates IContextDiscovery components by their descriptors.
+var DnsDiscoveryDescriptor = refer.NewDescriptor("pip-services", "discovery", "dns", "*", "1.0")
+
 // NewDefaultDiscoveryFactory create a new instance of the factory.
 //	Returns: *build.Factory
 func NewDefaultDiscoveryFactory() *build.Factory {
 	factory := build.NewFactory()
 
 	factory.RegisterType(MemoryDiscoveryDescriptor, NewEmptyMemoryDiscovery)
+	factory.RegisterType(FileDiscoveryDescriptor, NewEmptyFileDiscovery)
+	factory.RegisterType(DnsDiscoveryDescriptor, NewEmptyDnsDiscovery)
 
 	return factory
 }
@@ -0,0 +1,103 @@
+package connect
+
+import (
+	"context"
+
+	"github.com/pip-services3-gox/pip-services3-commons-gox/errors"
+)
+
+// DiscoveryContextAdapter adapts IDiscovery implementations to IContextDiscovery interface.
+// Context is not passed to the adapted service, and unregistration is supported
+// only when the service implements Unregister(correlationId, key, connection) method.
+//	see IDiscovery
+//	see IContextDiscovery
+type DiscoveryContextAdapter struct {
+	discovery IDiscovery
+}
+
+// NewDiscoveryContextAdapter creates a new instance of the adapter.
+//	Parameters:
+//		- discovery IDiscovery a discovery service to adapt.
+//	Returns: *DiscoveryContextAdapter
+func NewDiscoveryContextAdapter(discovery IDiscovery) *DiscoveryContextAdapter {
+	return &DiscoveryContextAdapter{
+		discovery: discovery,
+	}
+}
+
+// Discovery gets the adapted discovery service.
+//	Returns: IDiscovery
+func (c *DiscoveryContextAdapter) Discovery() IDiscovery {
+	return c.discovery
+}
+
+// Register connection parameters into the discovery service.
+//	Parameters:
+//		- ctx context.Context
+//		- correlationId string transaction id to trace execution through call chain.
+//		- key string a key to uniquely identify the connection parameters.
+//		- connection *ConnectionParams
+//	Returns: *ConnectionParams, error registered connection or error.
+func (c *DiscoveryContextAdapter) Register(ctx context.Context, correlationId string, key string,
+	connection *ConnectionParams) (result *ConnectionParams, err error) {
+
+	return c.discovery.Register(correlationId, key, connection)
+}
+
+// Unregister removes connection parameters from the discovery service.
+//	Parameters:
+//		- ctx context.Context
+//		- correlationId string transaction id to trace execution through call chain.
+//		- key string a key to uniquely identify the connection parameters.
+//		- connection *ConnectionParams a connection to remove or nil to remove all connections.
+//	Returns: error or nil if no errors occurred.
+func (c *DiscoveryContextAdapter) Unregister(ctx context.Context, correlationId string, key string,
+	connection *ConnectionParams) error {
+
+	if discovery, ok := c.discovery.(interface {
+		Unregister(correlationId string, key string, connection *ConnectionParams) error
+	}); ok {
+		return discovery.Unregister(correlationId, key, connection)
+	}
+
+	return errors.NewUnsupportedError(
+		correlationId,
+		"UNREGISTER_NOT_SUPPORTED",
+		"Discovery service doesn't support unregistration",
+	).WithDetails("key", key)
+}
+
+// ResolveOne a single connection parameters by its key.
+//	Parameters:
+//		- ctx context.Context
+//		- correlationId string transaction id to trace execution through call chain.
+//		- key string a key to uniquely identify the connection.
+//	Returns: *ConnectionParams, error receives found connection or error.
+func (c *DiscoveryContextAdapter) ResolveOne(ctx context.Context, correlationId string,
+	key string) (result *ConnectionParams, err error) {
+
+	return c.discovery.ResolveOne(correlationId, key)
+}
+
+// ResolveAll connection parameters by its key.
+//	Parameters:
+//		- ctx context.Context
+//		- correlationId string transaction id to trace execution through call chain.
+//		- key string a key to uniquely identify the connection.
+//	Returns: []*ConnectionParams, error receives found connections or error.
+func (c *DiscoveryContextAdapter) ResolveAll(ctx context.Context, correlationId string,
+	key string) (result []*ConnectionParams, err error) {
+
+	return c.discovery.ResolveAll(correlationId, key)
+}
+
+// toDiscovery converts a referenced component into IContextDiscovery adapting IDiscovery services.
+func toDiscovery(component any) (IContextDiscovery, bool) {
+	if discovery, ok := component.(IContextDiscovery); ok && discovery != nil {
+		return discovery, true
+	}
+	if discovery, ok := component.(IDiscovery); ok && discovery != nil {
+		return NewDiscoveryContextAdapter(discovery), true
+	}
+	return nil, false
+}
@@ -0,0 +1,325 @@
+package connect
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pip-services3-gox/pip-services3-commons-gox/config"
+	"github.com/pip-services3-gox/pip-services3-commons-gox/errors"
+)
+
+// IDnsResolver interface for DNS resolvers used by DnsDiscovery.
+// It is implemented by *net.Resolver and can be replaced to run without network access.
+type IDnsResolver interface {
+	// LookupSRV resolves SRV records. When service and proto are empty, name is looked up directly.
+	LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error)
+
+	// LookupHost resolves host addresses from A and AAAA records.
+	LookupHost(ctx context.Context, host string) ([]string, error)
+}
+
+// Types of DNS records used to resolve discovery keys.
+const (
+	// DnsRecordTypeAuto uses SRV records for keys like _http._tcp.service and A records for other keys
+	DnsRecordTypeAuto = "auto"
+	// DnsRecordTypeSrv uses SRV records
+	DnsRecordTypeSrv = "srv"
+	// DnsRecordTypeA uses A and AAAA records
+	DnsRecordTypeA = "a"
+)
+
+// DnsDiscovery discovery service that resolves discovery keys into connections using DNS.
+// It fits headless services in Kubernetes that expose instances via DNS records.
+// Keys like "_http._tcp.myservice.default.svc.cluster.local" are resolved by SRV records
+// into protocol, host, port, priority and weight, and other keys are resolved by A records
+// into hosts with the default protocol and port. Resolved connections are cached for
+// the configured ttl period, which doesn't follow TTLs of DNS records, and the stale cache
+// is used when DNS is not available. Callers receive copies of cached connections.
+// Registrations are managed by DNS, so Register and Unregister don't change anything.
+//
+//	Configuration parameters
+//		- options:
+//			- record_type: DNS records to use: auto, srv or a (default: auto)
+//			- protocol: default connection protocol for A records (default: empty)
+//			- port: default port for A records (default: 0)
+//			- ttl: fixed time in milliseconds to cache resolved connections (default: 30000)
+//			- timeout: DNS lookup timeout in milliseconds (default: 5000)
+//	see IContextDiscovery
+//	see IDnsResolver
+//	Example
+//		discovery := NewEmptyDnsDiscovery();
+//		discovery.Configure(context.Background(), NewConfigParamsFromTuples(
+//			"options.protocol", "http",
+//			"options.port", 8080,
+//		));
+//		connections, err := discovery.ResolveAll(context.Background(), "123", "myservice.default.svc.cluster.local");
+type DnsDiscovery struct {
+	resolver   IDnsResolver
+	recordType string
+	protocol   string
+	port       int
+	ttl        time.Duration
+	timeout    time.Duration
+	cache      map[string]*dnsDiscoveryCacheEntry
+	mtx        sync.Mutex
+}
+
+var _ IContextDiscovery = (*DnsDiscovery)(nil)
+
+// dnsDiscoveryCacheEntry keeps resolved connections until they expire.
+type dnsDiscoveryCacheEntry struct {
+	connections []*ConnectionParams
+	expiresAt   time.Time
+}
+
+const (
+	// DnsDiscoveryRecordTypeKey is a constant for record type key
+	DnsDiscoveryRecordTypeKey = "options.record_type"
+	// DnsDiscoveryProtocolKey is a constant for default protocol key
+	DnsDiscoveryProtocolKey = "options.protocol"
+	// DnsDiscoveryPortKey is a constant for default port key
+	DnsDiscoveryPortKey = "options.port"
+	// DnsDiscoveryTtlKey is a constant for cache TTL key
+	DnsDiscoveryTtlKey = "options.ttl"
+	// DnsDiscoveryTimeoutKey is a constant for lookup timeout key
+	DnsDiscoveryTimeoutKey = "options.timeout"
+)
+
+// NewEmptyDnsDiscovery creates a new instance of discovery service with the default DNS resolver.
+//
+//	Returns: *DnsDiscovery
+func NewEmptyDnsDiscovery() *DnsDiscovery {
+	return NewDnsDiscovery(nil)
+}
+
+// NewDnsDiscovery creates a new instance of discovery service.
+//
+//	Parameters:
+//		- resolver IDnsResolver a DNS resolver or nil to use net.DefaultResolver.
+//	Returns: *DnsDiscovery
+func NewDnsDiscovery(resolver IDnsResolver) *DnsDiscovery {
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+	return &DnsDiscovery{
+		resolver:   resolver,
+		recordType: DnsRecordTypeAuto,
+		ttl:        30 * time.Second,
+		timeout:    5 * time.Second,
+		cache:      map[string]*dnsDiscoveryCacheEntry{},
+	}
+}
+
+// Configure component by passing configuration parameters.
+//
+//	Parameters:
+//		- ctx context.Context
+//		- config *config.ConfigParams configuration parameters to be set.
+func (c *DnsDiscovery) Configure(ctx context.Context, config *config.ConfigParams) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.recordType = strings.ToLower(config.GetAsStringWithDefault(DnsDiscoveryRecordTypeKey, c.recordType))
+	c.protocol = config.GetAsStringWithDefault(DnsDiscoveryProtocolKey, c.protocol)
+	c.port = config.GetAsIntegerWithDefault(DnsDiscoveryPortKey, c.port)
+	c.ttl = time.Duration(config.GetAsLongWithDefault(DnsDiscoveryTtlKey, c.ttl.Milliseconds())) * time.Millisecond
+	c.timeout = time.Duration(config.GetAsLongWithDefault(DnsDiscoveryTimeoutKey, c.timeout.Milliseconds())) * time.Millisecond
+	c.cache = map[string]*dnsDiscoveryCacheEntry{}
+}
+
+// SetResolver sets the DNS resolver and clears the cache.
+//
+//	Parameters:
+//		- resolver IDnsResolver a DNS resolver or nil to use net.DefaultResolver.
+func (c *DnsDiscovery) SetResolver(resolver IDnsResolver) {
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.resolver = resolver
+	c.cache = map[string]*dnsDiscoveryCacheEntry{}
+}
+
+// ClearCache removes all cached connections, so next calls resolve them again.
+func (c *DnsDiscovery) ClearCache() {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.cache = map[string]*dnsDiscoveryCacheEntry{}
+}
+
+// Register does nothing since registrations are managed by DNS.
+//
+//	Parameters:
+//		- ctx context.Context
+//		- correlationId string transaction id to trace execution through call chain.
+//		- key string a key to uniquely identify the connection parameters.
+//		- connection *ConnectionParams
+//	Returns: *ConnectionParams, error the connection and nil error.
+func (c *DnsDiscovery) Register(ctx context.Context, correlationId string, key string,
+	connection *ConnectionParams) (result *ConnectionParams, err error) {
+
+	return connection, nil
+}
+
+// Unregister does nothing since registrations are managed by DNS.
+//
+//	Parameters:
+//		- ctx context.Context
+//		- correlationId string transaction id to trace execution through call chain.
+//		- key string a key to uniquely identify the connection parameters.
+//		- connection *ConnectionParams
+//	Returns: error always nil.
+func (c *DnsDiscovery) Unregister(ctx context.Context, correlationId string, key string,
+	connection *ConnectionParams) error {
+
+	return nil
+}
+
+// ResolveOne a single connection parameters by its key.
+//
+//	Parameters:
+//		- ctx context.Context
+//		- correlationId: string transaction id to trace execution through call chain.
+//		- key: string a DNS name to resolve.
+//	Returns: *ConnectionParams, error receives found connection or error.
+func (c *DnsDiscovery) ResolveOne(ctx context.Context, correlationId string,
+	key string) (result *ConnectionParams, err error) {
+
+	connections, err := c.ResolveAll(ctx, correlationId, key)
+	if err != nil {
+		return nil, err
+	}
+	if len(connections) > 0 {
+		return connections[0], nil
+	}
+
+	return nil, nil
+}
+
+// ResolveAll connection parameters by its key.
+//
+//	Parameters:
+//		- ctx context.Context
+//		- correlationId: string transaction id to trace execution through call chain.
+//		- key: string a DNS name to resolve.
+//	Returns: []*ConnectionParams, error receives found connections or error.
+func (c *DnsDiscovery) ResolveAll(ctx context.Context, correlationId string,
+	key string) (result []*ConnectionParams, err error) {
+
+	c.mtx.Lock()
+	entry := c.cache[key]
+	resolver := c.resolver
+	recordType := c.recordType
+	protocol := c.protocol
+	port := c.port
+	ttl := c.ttl
+	timeout := c.timeout
+	c.mtx.Unlock()
+
+	if entry != nil && time.Now().Before(entry.expiresAt) {
+		return copyDnsConnections(entry.connections), nil
+	}
+
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	var connections []*ConnectionParams
+	useSrv := recordType == DnsRecordTypeSrv || (recordType != DnsRecordTypeA && strings.HasPrefix(key, "_"))
+	if useSrv {
+		connections, err = resolveDnsSrv(ctx, resolver, key)
+	} else {
+		connections, err = resolveDnsHost(ctx, resolver, key, protocol, port)
+	}
+
+	if err != nil {
+		if entry != nil {
+			// Stale connections are better than none while DNS is not available
+			return copyDnsConnections(entry.connections), nil
+		}
+		return nil, errors.NewConnectionError(
+			correlationId, "DNS_LOOKUP_FAILED", "Failed to resolve "+key+" in DNS",
+		).WithDetails("key", key).WithCause(err)
+	}
+
+	c.mtx.Lock()
+	c.cache[key] = &dnsDiscoveryCacheEntry{
+		connections: connections,
+		expiresAt:   time.Now().Add(ttl),
+	}
+	c.mtx.Unlock()
+
+	return copyDnsConnections(connections), nil
+}
+
+// copyDnsConnections copies cached connections, so callers can change them without affecting the cache.
+func copyDnsConnections(connections []*ConnectionParams) []*ConnectionParams {
+	result := make([]*ConnectionParams, 0, len(connections))
+	for _, connection := range connections {
+		result = append(result, NewConnectionParams(connection.Value()))
+	}
+	return result
+}
+
+// resolveDnsSrv resolves SRV records like _http._tcp.myservice into connections.
+func resolveDnsSrv(ctx context.Context, resolver IDnsResolver, name string) ([]*ConnectionParams, error) {
+	_, records, err := resolver.LookupSRV(ctx, "", "", name)
+	if err != nil {
+		return nil, err
+	}
+
+	protocol := ""
+	if labels := strings.Split(name, "."); len(labels) > 1 && strings.HasPrefix(labels[0], "_") {
+		protocol = strings.TrimPrefix(labels[0], "_")
+	}
+
+	connections := make([]*ConnectionParams, 0, len(records))
+	for _, record := range records {
+		connection := NewEmptyConnectionParams()
+		if protocol != "" {
+			connection.SetProtocol(protocol)
+		}
+		connection.SetHost(strings.TrimSuffix(record.Target, "."))
+		connection.SetPort(int(record.Port))
+		connection.SetPriority(int(record.Priority))
+		connection.SetWeight(int(record.Weight))
+		connections = append(connections, connection)
+	}
+	return connections, nil
+}
+
+// resolveDnsHost resolves A and AAAA records into connections with the default protocol and port.
+func resolveDnsHost(ctx context.Context, resolver IDnsResolver, name string,
+	protocol string, port int) ([]*ConnectionParams, error) {
+
+	addresses, err := resolver.LookupHost(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+
+	connections := make([]*ConnectionParams, 0, len(addresses))
+	for _, address := range addresses {
+		connection := NewEmptyConnectionParams()
+		if protocol != "" {
+			connection.SetProtocol(protocol)
+		}
+		connection.SetHost(address)
+		if port > 0 {
+			connection.SetPort(port)
+		}
+		connections = append(connections, connection)
+	}
+	return connections, nil
+}
@@ -0,0 +1,369 @@
+package connect
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/pip-services3-gox/pip-services3-commons-gox/config"
+	"github.com/pip-services3-gox/pip-services3-commons-gox/convert"
+	"github.com/pip-services3-gox/pip-services3-commons-gox/errors"
+	"gopkg.in/yaml.v2"
+)
+
+// FileDiscovery discovery service that reads connections from a shared JSON or YAML file,
+// similar to a hosts file. Each top-level key is a discovery key with a connection object,
+// a list of connection objects or a connection URI string. The file is checked for changes
+// not more often than the check interval and re-read when its modification time or size changes.
+// When the file becomes invalid the last successfully read connections are kept.
+// Connections registered at runtime are kept in memory of the current process
+// in addition to connections from the file.
+//
+//	Configuration parameters
+//		- path: path to the discovery file
+//		- options:
+//			- check_interval: interval in milliseconds to check the file for changes (default: 1000)
+//	see IContextDiscovery
+//	see ConnectionParams
+//	Example
+//		======== discovery.yml ========
+//		service1:
+//		  - host: 10.1.1.100
+//		    port: 8080
+//		  - host: 10.1.1.101
+//		    port: 8080
+//		service2:
+//		  host: 10.1.1.102
+//		  port: 8082
+//		service3: "mongodb://10.1.1.103:27017/test"
+//		===============================
+//
+//		discovery := NewFileDiscovery("discovery.yml");
+//		connections, err := discovery.ResolveAll(context.Background(), "123", "service1");
+type FileDiscovery struct {
+	path          string
+	checkInterval int64
+	items         map[string][]*ConnectionParams
+	registered    map[string][]*ConnectionParams
+	loaded        bool
+	modTime       time.Time
+	size          int64
+	lastCheck     time.Time
+	mtx           sync.Mutex
+}
+
+var _ IContextDiscovery = (*FileDiscovery)(nil)
+
+const (
+	// FileDiscoveryPathKey is a constant for path key
+	FileDiscoveryPathKey = "path"
+	// FileDiscoveryCheckIntervalKey is a constant for check interval key
+	FileDiscoveryCheckIntervalKey = "options.check_interval"
+)
+
+// NewEmptyFileDiscovery creates a new instance of discovery service.
+//
+//	Returns: *FileDiscovery
+func NewEmptyFileDiscovery() *FileDiscovery {
+	return NewFileDiscovery("")
+}
+
+// NewFileDiscovery creates a new instance of discovery service.
+//
+//	Parameters:
+//		- path string a path to the discovery file.
+//	Returns: *FileDiscovery
+func NewFileDiscovery(path string) *FileDiscovery {
+	return &FileDiscovery{
+		path:          path,
+		checkInterval: 1000,
+		items:         map[string][]*ConnectionParams{},
+		registered:    map[string][]*ConnectionParams{},
+	}
+}
+
+// Configure component by passing configuration parameters.
+//
+//	Parameters:
+//		- ctx context.Context
+//		- config *config.ConfigParams configuration parameters to be set.
+func (c *FileDiscovery) Configure(ctx context.Context, config *config.ConfigParams) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	path := config.GetAsStringWithDefault(FileDiscoveryPathKey, c.path)
+	if path != c.path {
+		c.path = path
+		c.loaded = false
+	}
+	c.checkInterval = config.GetAsLongWithDefault(FileDiscoveryCheckIntervalKey, c.checkInterval)
+}
+
+// Path gets the path to the discovery file.
+//
+//	Returns: string the path to the discovery file.
+func (c *FileDiscovery) Path() string {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	return c.path
+}
+
+// SetPath sets the path to the discovery file.
+//
+//	Parameters:
+//		- path string a new path to the discovery file.
+func (c *FileDiscovery) SetPath(path string) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.path = path
+	c.loaded = false
+}
+
+// refresh re-reads the file when it changed. It must be called under the lock.
+func (c *FileDiscovery) refresh(correlationId string) error {
+	now := time.Now()
+	if c.loaded && now.Sub(c.lastCheck) < time.Duration(c.checkInterval)*time.Millisecond {
+		return nil
+	}
+	c.lastCheck = now
+
+	if c.path == "" {
+		return errors.NewConfigError(correlationId, "NO_PATH", "Missing discovery file path")
+	}
+
+	info, err := os.Stat(c.path)
+	if err != nil {
+		if c.loaded {
+			return nil
+		}
+		return errors.NewFileError(
+			correlationId, "READ_FAILED", "Failed to read discovery file "+c.path,
+		).WithDetails("path", c.path).WithCause(err)
+	}
+	if c.loaded && info.ModTime().Equal(c.modTime) && info.Size() == c.size {
+		return nil
+	}
+
+	items, err := readDiscoveryFile(correlationId, c.path)
+	if err != nil {
+		if c.loaded {
+			// Keep the last good connections while the file is being edited
+			return nil
+		}
+		return err
+	}
+
+	c.items = items
+	c.modTime = info.ModTime()
+	c.size = info.Size()
+	c.loaded = true
+	return nil
+}
+
+// readDiscoveryFile reads connections from JSON or YAML file.
+func readDiscoveryFile(correlationId string, path string) (map[string][]*ConnectionParams, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.NewFileError(
+			correlationId, "READ_FAILED", "Failed to read discovery file "+path,
+		).WithDetails("path", path).WithCause(err)
+	}
+
+	// YAML parser accepts JSON as well
+	var content any
+	if err = yaml.Unmarshal(data, &content); err != nil {
+		return nil, errors.NewConfigError(
+			correlationId, "INVALID_DISCOVERY_FILE", "Failed to parse discovery file "+path,
+		).WithDetails("path", path).WithCause(err)
+	}
+
+	result := make(map[string][]*ConnectionParams)
+	if content == nil {
+		return result, nil
+	}
+
+	entries, ok := content.(map[any]any)
+	if !ok {
+		return nil, errors.NewConfigError(
+			correlationId, "INVALID_DISCOVERY_FILE", "Discovery file "+path+" must contain an object with discovery keys",
+		).WithDetails("path", path)
+	}
+
+	for k, v := range entries {
+		key := convert.StringConverter.ToString(k)
+		connections, err := toDiscoveryConnections(v)
+		if err != nil {
+			return nil, errors.NewConfigError(
+				correlationId, "INVALID_DISCOVERY_FILE", "Invalid connections for key "+key+" in discovery file "+path,
+			).WithDetails("path", path).WithDetails("key", key).WithCause(err)
+		}
+		result[key] = connections
+	}
+	return result, nil
+}
+
+// toDiscoveryConnections converts a file entry into connections.
+func toDiscoveryConnections(value any) ([]*ConnectionParams, error) {
+	switch v := value.(type) {
+	case nil:
+		return []*ConnectionParams{}, nil
+	case string:
+		return []*ConnectionParams{NewConnectionParamsFromTuples(ConnectionParamURI, v)}, nil
+	case map[any]any:
+		return []*ConnectionParams{NewConnectionParamsFromValue(v)}, nil
+	case []any:
+		result := make([]*ConnectionParams, 0, len(v))
+		for _, item := range v {
+			connections, err := toDiscoveryConnections(item)
+			if err != nil {
+				return nil, err
+			}
+			result = append(result, connections...)
+		}
+		return result, nil
+	}
+	return nil, fmt.Errorf("unsupported connection value %v", value)
+}
+
+// Keys gets discovery keys defined in the file or registered at runtime.
+//
+//	Parameters:
+//		- ctx context.Context
+//		- correlationId string transaction id to trace execution through call chain.
+//	Returns: []string, error the sorted list of keys or error if the file cannot be read.
+func (c *FileDiscovery) Keys(ctx context.Context, correlationId string) ([]string, error) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if err := c.refresh(correlationId); err != nil {
+		return nil, err
+	}
+
+	found := make(map[string]bool)
+	for key := range c.items {
+		found[key] = true
+	}
+	for key := range c.registered {
+		found[key] = true
+	}
+
+	result := make([]string, 0, len(found))
+	for key := range found {
+		result = append(result, key)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// Register connection parameters into the discovery service.
+// Registered connections are kept in memory and are not written into the file.
+//
+//	Parameters:
+//		- ctx context.Context
+//		- correlationId string transaction id to trace execution through call chain.
+//		- key string a key to uniquely identify the connection parameters.
+//		- connection *ConnectionParams
+//	Returns: *ConnectionParams, error registered connection or error.
+func (c *FileDiscovery) Register(ctx context.Context, correlationId string, key string,
+	connection *ConnectionParams) (result *ConnectionParams, err error) {
+
+	if connection == nil {
+		return nil, nil
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	for _, item := range c.registered[key] {
+		if item == connection || item.String() == connection.String() {
+			return connection, nil
+		}
+	}
+	c.registered[key] = append(c.registered[key], connection)
+	return connection, nil
+}
+
+// Unregister removes connection parameters registered at runtime.
+// Connections defined in the file are not affected.
+//
+//	Parameters:
+//		- ctx context.Context
+//		- correlationId string transaction id to trace execution through call chain.
+//		- key string a key to uniquely identify the connection parameters.
+//		- connection *ConnectionParams a connection to remove or nil to remove all registered connections.
+//	Returns: error or nil if no errors occurred.
+func (c *FileDiscovery) Unregister(ctx context.Context, correlationId string, key string,
+	connection *ConnectionParams) error {
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if connection == nil {
+		delete(c.registered, key)
+		return nil
+	}
+
+	connections := make([]*ConnectionParams, 0)
+	for _, item := range c.registered[key] {
+		if item != connection && item.String() != connection.String() {
+			connections = append(connections, item)
+		}
+	}
+
+	if len(connections) > 0 {
+		c.registered[key] = connections
+	} else {
+		delete(c.registered, key)
+	}
+	return nil
+}
+
+// ResolveOne a single connection parameters by its key.
+//
+//	Parameters:
+//		- ctx context.Context
+//		- correlationId: string transaction id to trace execution through call chain.
+//		- key: string a key to uniquely identify the connection.
+//	Returns: *ConnectionParams, error receives found connection or error.
+func (c *FileDiscovery) ResolveOne(ctx context.Context, correlationId string,
+	key string) (result *ConnectionParams, err error) {
+
+	connections, err := c.ResolveAll(ctx, correlationId, key)
+	if err != nil {
+		return nil, err
+	}
+	if len(connections) > 0 {
+		return connections[0], nil
+	}
+
+	return nil, nil
+}
+
+// ResolveAll connection parameters by its key.
+// Connections from the file go before connections registered at runtime.
+//
+//	Parameters:
+//		- ctx context.Context
+//		- correlationId: string transaction id to trace execution through call chain.
+//		- key: string a key to uniquely identify the connection.
+//	Returns: []*ConnectionParams, error receives found connections or error.
+func (c *FileDiscovery) ResolveAll(ctx context.Context, correlationId string,
+	key string) (result []*ConnectionParams, err error) {
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	if err = c.refresh(correlationId); err != nil {
+		return nil, err
+	}
+
+	connections := make([]*ConnectionParams, 0, len(c.items[key])+len(c.registered[key]))
+	connections = append(connections, c.items[key]...)
+	connections = append(connections, c.registered[key]...)
+	return connections, nil
+}
@@ -1,7 +1,12 @@
 package connect
 
+import "context"
+
 // IDiscovery interface for discovery services which are used to store and resolve
 // connection parameters to connect to external services.
+// It is kept for existing third-party services. Discovery services in this package
+// implement IContextDiscovery, and IDiscovery services are wrapped with DiscoveryContextAdapter.
+//	see IContextDiscovery
 type IDiscovery interface {
 
 	// Register connection parameters into the discovery service.
@@ -14,3 +19,27 @@ type IDiscovery interface {
 	// ResolveAll all connection parameters by their key.
 	ResolveAll(correlationId string, key string) (result []*ConnectionParams, err error)
 }
+
+// IContextDiscovery interface for discovery services that accept context.Context
+// to cancel requests and support removal of registered connections.
+// It is implemented by MemoryDiscovery, FileDiscovery and DnsDiscovery.
+// ConnectionResolver uses both IContextDiscovery and IDiscovery services.
+//	see IDiscovery
+//	see DiscoveryContextAdapter
+type IContextDiscovery interface {
+
+	// Register connection parameters into the discovery service.
+	Register(ctx context.Context, correlationId string, key string,
+		connection *ConnectionParams) (result *ConnectionParams, err error)
+
+	// Unregister removes previously registered connection parameters from the discovery service.
+	// When connection is nil all connections registered under the key are removed.
+	Unregister(ctx context.Context, correlationId string, key string,
+		connection *ConnectionParams) error
+
+	// ResolveOne a single connection parameters by its key.
+	ResolveOne(ctx context.Context, correlationId string, key string) (result *ConnectionParams, err error)
+
+	// ResolveAll all connection parameters by their key.
+	ResolveAll(ctx context.Context, correlationId string, key string) (result []*ConnectionParams, err error)
+}
@@ -0,0 +1,53 @@
+package connect
+
+import "context"
+
+// DiscoveryEvent describes a change of connections registered under a discovery key.
+type DiscoveryEvent struct {
+	Key         string              // A discovery key
+	Connections []*ConnectionParams // All resolvable connections after the change
+	Added       []*ConnectionParams // Connections that became resolvable since the previous event
+	Removed     []*ConnectionParams // Connections that are no longer resolvable since the previous event
+}
+
+// IWatchableDiscovery interface for discovery services that notify clients
+// when connections registered under a key come and go.
+//	see IContextDiscovery
+type IWatchableDiscovery interface {
+	IContextDiscovery
+
+	// Watch starts watching connections registered under the key.
+	// The first event contains the current connections, and following events are sent
+	// when the set of resolvable connections changes. Intermediate changes may be coalesced
+	// when the client reads events slower than they happen. The channel is closed
+	// when the context is cancelled.
+	Watch(ctx context.Context, correlationId string, key string) (<-chan *DiscoveryEvent, error)
+}
+
+// diffConnections compares two sets of connections by their parameters.
+func diffConnections(previous []*ConnectionParams,
+	current []*ConnectionParams) (added []*ConnectionParams, removed []*ConnectionParams) {
+
+	previousKeys := make(map[string]bool, len(previous))
+	for _, connection := range previous {
+		previousKeys[connection.String()] = true
+	}
+	currentKeys := make(map[string]bool, len(current))
+	for _, connection := range current {
+		currentKeys[connection.String()] = true
+	}
+
+	added = make([]*ConnectionParams, 0)
+	for _, connection := range current {
+		if !previousKeys[connection.String()] {
+			added = append(added, connection)
+		}
+	}
+	removed = make([]*ConnectionParams, 0)
+	for _, connection := range previous {
+		if !currentKeys[connection.String()] {
+			removed = append(removed, connection)
+		}
+	}
+	return added, removed
+}
@@ -2,18 +2,44 @@ package connect
 
 import (
 	"context"
+	"sync"
+	"time"
 
 	"github.com/pip-services3-gox/pip-services3-commons-gox/config"
+	"github.com/pip-services3-gox/pip-services3-commons-gox/errors"
 )
 
+// Health statuses of instances registered in discovery services.
+const (
+	// DiscoveryHealthPassing means the instance is healthy
+	DiscoveryHealthPassing = "passing"
+	// DiscoveryHealthWarning means the instance works with degraded quality but can be used
+	DiscoveryHealthWarning = "warning"
+	// DiscoveryHealthCritical means the instance shall not be used
+	DiscoveryHealthCritical = "critical"
+)
+
+// DiscoveryInstance describes a connection registered in a discovery service.
+type DiscoveryInstance struct {
+	Key        string            // A key the connection is registered under
+	Connection *ConnectionParams // Connection parameters
+	Health     string            // Health status of the instance
+	ExpiresAt  time.Time         // Time when the registration expires or zero time if it never expires
+}
+
 // MemoryDiscovery discovery service that keeps connections in memory.
+// It is safe for concurrent use. Registrations may have a time-to-live that is renewed
+// by heartbeats, and expired instances are removed automatically. Each instance has
+// a health status, and instances with critical status are not resolved.
+// Clients can watch keys to be notified when resolvable connections change.
 //
 //	Configuration parameters
 //		[connection key 1]:
 //		... connection parameters for key 1
 //		[connection key 2]:
 //		... connection parameters for key N
-//	see IDiscovery
+//	see IContextDiscovery
+//	see IWatchableDiscovery
 //	see ConnectionParams
 //	Example
 //		config := NewConfigParamsFromTuples(
@@ -24,11 +50,60 @@ import (
 //		);
 //		discovery := NewMemoryDiscovery();
 //		discovery.ReadConnections(config);
-//		conn, err := discovery.ResolveOne("123", "key1");
+//		conn, err := discovery.ResolveOne(context.Background(), "123", "key1");
 //
 // Result: host=10.1.1.100;port=8080
+//
+//	Example of registration with heartbeats
+//		discovery.RegisterWithTtl(ctx, "123", "service1", connection, 30 * time.Second)
+//		...
+//		// Every 10 seconds
+//		err := discovery.Heartbeat(ctx, "123", "service1", connection)
 type MemoryDiscovery struct {
-	items map[string][]*ConnectionParams
+	items       map[string][]*memoryDiscoveryEntry
+	ttl         time.Duration
+	watchers    map[string]map[*memoryDiscoveryWatcher]bool
+	expiryTimer *time.Timer
+	mtx         sync.RWMutex
+}
+
+var _ IContextDiscovery = (*MemoryDiscovery)(nil)
+var _ IWatchableDiscovery = (*MemoryDiscovery)(nil)
+
+// memoryDiscoveryWatcher is signalled when connections under the watched key may have changed.
+type memoryDiscoveryWatcher struct {
+	signal chan struct{}
+}
+
+func (w *memoryDiscoveryWatcher) notify() {
+	select {
+	case w.signal <- struct{}{}:
+	default:
+	}
+}
+
+// memoryDiscoveryEntry keeps a registered connection with its state.
+type memoryDiscoveryEntry struct {
+	connection *ConnectionParams
+	health     string
+	ttl        time.Duration
+	expiresAt  time.Time
+}
+
+func (e *memoryDiscoveryEntry) expired(now time.Time) bool {
+	return !e.expiresAt.IsZero() && !now.Before(e.expiresAt)
+}
+
+func (e *memoryDiscoveryEntry) renew(now time.Time) {
+	if e.ttl > 0 {
+		e.expiresAt = now.Add(e.ttl)
+	} else {
+		e.expiresAt = time.Time{}
+	}
+}
+
+func (e *memoryDiscoveryEntry) matches(connection *ConnectionParams) bool {
+	return e.connection == connection || e.connection.String() == connection.String()
 }
 
 // NewEmptyMemoryDiscovery creates a new instance of discovery service.
@@ -36,7 +111,7 @@ type MemoryDiscovery struct {
 //	Returns: *MemoryDiscovery
 func NewEmptyMemoryDiscovery() *MemoryDiscovery {
 	return &MemoryDiscovery{
-		items: map[string][]*ConnectionParams{},
+		items: map[string][]*memoryDiscoveryEntry{},
 	}
 }
 
@@ -48,7 +123,7 @@ func NewEmptyMemoryDiscovery() *MemoryDiscovery {
 //	Returns: *MemoryDiscovery
 func NewMemoryDiscovery(ctx context.Context, config *config.ConfigParams) *MemoryDiscovery {
 	c := &MemoryDiscovery{
-		items: map[string][]*ConnectionParams{},
+		items: map[string][]*memoryDiscoveryEntry{},
 	}
 
 	if config != nil {
@@ -67,56 +142,289 @@ func (c *MemoryDiscovery) Configure(ctx context.Context, config *config.ConfigPa
 	c.ReadConnections(config)
 }
 
-// ReadConnections from configuration parameters. Each section represents an individual Connectionparams
+// ReadConnections from configuration parameters. Each section represents an individual Connectionparams.
+// Configured connections never expire.
 //
 //	Parameters:
 //		- ctx context.Context
 //		- config *configure.ConfigParams configuration parameters to be read
 func (c *MemoryDiscovery) ReadConnections(config *config.ConfigParams) {
-	c.items = make(map[string][]*ConnectionParams)
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.items = make(map[string][]*memoryDiscoveryEntry)
 
 	if config.Len() > 0 {
 		connectionSections := config.GetSectionNames()
 		for _, key := range connectionSections {
 			connection := config.GetSection(key)
-			c.items[key] = []*ConnectionParams{NewConnectionParamsFromValue(connection)}
+			c.items[key] = []*memoryDiscoveryEntry{{
+				connection: NewConnectionParamsFromValue(connection),
+				health:     DiscoveryHealthPassing,
+			}}
 		}
 	}
+
+	for key := range c.watchers {
+		c.notifyWatchers(key)
+	}
+	c.scheduleExpiry()
+}
+
+// Ttl gets the default time-to-live of registrations.
+//
+//	Returns: time.Duration the default time-to-live or 0 if registrations never expire.
+func (c *MemoryDiscovery) Ttl() time.Duration {
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	return c.ttl
+}
+
+// SetTtl sets the default time-to-live of registrations made by Register.
+//
+//	Parameters:
+//		- ttl time.Duration a new default time-to-live or 0 to never expire registrations.
+func (c *MemoryDiscovery) SetTtl(ttl time.Duration) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.ttl = ttl
 }
 
-// Register connection parameters into the discovery service.
+// Register connection parameters into the discovery service with the default time-to-live.
+// Registering the same connection again renews its registration.
 //
+//	see SetTtl
 //	Parameters:
+//		- ctx context.Context
 //		- correlationId string transaction id to trace execution through call chain.
 //		- key string a key to uniquely identify the connection parameters.
 //		- connection *ConnectionParams
 //	Returns: *ConnectionParams, error registered connection or error.
-func (c *MemoryDiscovery) Register(correlationId string, key string,
+func (c *MemoryDiscovery) Register(ctx context.Context, correlationId string, key string,
 	connection *ConnectionParams) (result *ConnectionParams, err error) {
 
-	if connection != nil {
-		connections, ok := c.items[key]
-		if ok && connections != nil {
-			connections = append(connections, connection)
-		} else {
-			connections = []*ConnectionParams{connection}
+	return c.RegisterWithTtl(ctx, correlationId, key, connection, c.Ttl())
+}
+
+// RegisterWithTtl registers connection parameters into the discovery service
+// for the given time-to-live. The registration is removed unless it is renewed
+// by Heartbeat before it expires.
+//
+//	Parameters:
+//		- ctx context.Context
+//		- correlationId string transaction id to trace execution through call chain.
+//		- key string a key to uniquely identify the connection parameters.
+//		- connection *ConnectionParams
+//		- ttl time.Duration a time-to-live of the registration or 0 to never expire it.
+//	Returns: *ConnectionParams, error registered connection or error.
+func (c *MemoryDiscovery) RegisterWithTtl(ctx context.Context, correlationId string, key string,
+	connection *ConnectionParams, ttl time.Duration) (result *ConnectionParams, err error) {
+
+	if connection == nil {
+		return nil, nil
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	now := time.Now()
+	c.removeExpired(now)
+
+	entry := c.find(key, connection)
+	if entry == nil {
+		entry = &memoryDiscoveryEntry{
+			connection: connection,
+			health:     DiscoveryHealthPassing,
 		}
-		c.items[key] = connections
+		c.items[key] = append(c.items[key], entry)
+		c.notifyWatchers(key)
 	}
+	entry.ttl = ttl
+	entry.renew(now)
+	c.scheduleExpiry()
 
 	return connection, nil
 }
 
+// find finds a registered entry. It must be called under the lock.
+func (c *MemoryDiscovery) find(key string, connection *ConnectionParams) *memoryDiscoveryEntry {
+	for _, entry := range c.items[key] {
+		if entry.matches(connection) {
+			return entry
+		}
+	}
+	return nil
+}
+
+// removeExpired removes expired registrations and notifies watchers.
+// It must be called under the write lock.
+func (c *MemoryDiscovery) removeExpired(now time.Time) {
+	for key, entries := range c.items {
+		live := entries[:0]
+		for _, entry := range entries {
+			if !entry.expired(now) {
+				live = append(live, entry)
+			}
+		}
+		if len(live) < len(entries) {
+			c.notifyWatchers(key)
+		}
+		if len(live) == 0 {
+			delete(c.items, key)
+		} else {
+			c.items[key] = live
+		}
+	}
+}
+
+// Heartbeat renews registration of the connection for another time-to-live period.
+// When the registration has already expired, the service shall register again.
+//
+//	Parameters:
+//		- ctx context.Context
+//		- correlationId string transaction id to trace execution through call chain.
+//		- key string a key to uniquely identify the connection parameters.
+//		- connection *ConnectionParams a registered connection.
+//	Returns: error a NotFoundError if the connection is not registered or nil if registration was renewed.
+func (c *MemoryDiscovery) Heartbeat(ctx context.Context, correlationId string, key string,
+	connection *ConnectionParams) error {
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	now := time.Now()
+	c.removeExpired(now)
+
+	entry := c.find(key, connection)
+	if entry == nil {
+		return errors.NewNotFoundError(
+			correlationId,
+			"NOT_REGISTERED",
+			"Connection is not registered under key "+key,
+		).WithDetails("key", key)
+	}
+
+	entry.renew(now)
+	c.scheduleExpiry()
+	return nil
+}
+
+// SetHealth sets health status of the registered connection.
+// Connections with critical status are not resolved until they become healthy again.
+//
+//	see DiscoveryHealthPassing
+//	see DiscoveryHealthWarning
+//	see DiscoveryHealthCritical
+//	Parameters:
+//		- ctx context.Context
+//		- correlationId string transaction id to trace execution through call chain.
+//		- key string a key to uniquely identify the connection parameters.
+//		- connection *ConnectionParams a registered connection.
+//		- health string a new health status.
+//	Returns: error a NotFoundError if the connection is not registered or nil if status was set.
+func (c *MemoryDiscovery) SetHealth(ctx context.Context, correlationId string, key string,
+	connection *ConnectionParams, health string) error {
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.removeExpired(time.Now())
+
+	entry := c.find(key, connection)
+	if entry == nil {
+		return errors.NewNotFoundError(
+			correlationId,
+			"NOT_REGISTERED",
+			"Connection is not registered under key "+key,
+		).WithDetails("key", key)
+	}
+
+	if entry.health != health {
+		entry.health = health
+		c.notifyWatchers(key)
+	}
+	return nil
+}
+
+// Instances gets all live instances registered under the key including unhealthy ones.
+//
+//	Parameters:
+//		- ctx context.Context
+//		- correlationId string transaction id to trace execution through call chain.
+//		- key string a key to uniquely identify the connection.
+//	Returns: []*DiscoveryInstance, error registered instances or error.
+func (c *MemoryDiscovery) Instances(ctx context.Context, correlationId string,
+	key string) ([]*DiscoveryInstance, error) {
+
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	now := time.Now()
+	result := make([]*DiscoveryInstance, 0)
+	for _, entry := range c.items[key] {
+		if entry.expired(now) {
+			continue
+		}
+		result = append(result, &DiscoveryInstance{
+			Key:        key,
+			Connection: entry.connection,
+			Health:     entry.health,
+			ExpiresAt:  entry.expiresAt,
+		})
+	}
+	return result, nil
+}
+
+// Unregister removes connection parameters from the discovery service.
+// Connections are matched by reference or by equal parameters.
+//
+//	Parameters:
+//		- ctx context.Context
+//		- correlationId string transaction id to trace execution through call chain.
+//		- key string a key to uniquely identify the connection parameters.
+//		- connection *ConnectionParams a connection to remove or nil to remove all connections under the key.
+//	Returns: error or nil if no errors occurred.
+func (c *MemoryDiscovery) Unregister(ctx context.Context, correlationId string, key string,
+	connection *ConnectionParams) error {
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	defer c.notifyWatchers(key)
+
+	if connection == nil {
+		delete(c.items, key)
+		return nil
+	}
+
+	entries := make([]*memoryDiscoveryEntry, 0)
+	for _, entry := range c.items[key] {
+		if !entry.matches(connection) {
+			entries = append(entries, entry)
+		}
+	}
+
+	if len(entries) > 0 {
+		c.items[key] = entries
+	} else {
+		delete(c.items, key)
+	}
+	return nil
+}
+
 // ResolveOne a single connection parameters by its key.
 //
 //	Parameters:
+//		- ctx context.Context
 //		- correlationId: string transaction id to trace execution through call chain.
 //		- key: string a key to uniquely identify the connection.
 //	Returns: *ConnectionParams, error receives found connection or error.
-func (c *MemoryDiscovery) ResolveOne(correlationId string,
+func (c *MemoryDiscovery) ResolveOne(ctx context.Context, correlationId string,
 	key string) (result *ConnectionParams, err error) {
 
-	connections, _ := c.ResolveAll(correlationId, key)
+	connections, _ := c.ResolveAll(ctx, correlationId, key)
 	if len(connections) > 0 {
 		return connections[0], nil
 	}
@@ -125,18 +433,139 @@ func (c *MemoryDiscovery) ResolveOne(correlationId string,
 }
 
 // ResolveAll connection parameters by its key.
+// Only live connections without critical health status are returned.
 //
 //	Parameters:
+//		- ctx context.Context
 //		- correlationId: string transaction id to trace execution through call chain.
 //		- key: string a key to uniquely identify the connection.
-//	Returns: *ConnectionParams, error receives found connection or error.
-func (c *MemoryDiscovery) ResolveAll(correlationId string,
+//	Returns: []*ConnectionParams, error receives found connections or error.
+func (c *MemoryDiscovery) ResolveAll(ctx context.Context, correlationId string,
 	key string) (result []*ConnectionParams, err error) {
-	connections := c.items[key]
 
-	if connections == nil {
-		connections = []*ConnectionParams{}
+	c.mtx.RLock()
+	defer c.mtx.RUnlock()
+
+	now := time.Now()
+	connections := make([]*ConnectionParams, 0)
+	for _, entry := range c.items[key] {
+		if !entry.expired(now) && entry.health != DiscoveryHealthCritical {
+			connections = append(connections, entry.connection)
+		}
 	}
 
 	return connections, nil
 }
+
+// Watch starts watching connections registered under the key. The first event contains
+// the current connections, and following events are sent when connections are registered,
+// unregistered, expire or change their health between critical and other statuses.
+// The channel is closed when the context is cancelled.
+//
+//	see IWatchableDiscovery
+//	Parameters:
+//		- ctx context.Context a context to stop watching.
+//		- correlationId: string transaction id to trace execution through call chain.
+//		- key: string a key to uniquely identify the connection.
+//	Returns: <-chan *DiscoveryEvent, error a stream of connection changes or error.
+func (c *MemoryDiscovery) Watch(ctx context.Context, correlationId string,
+	key string) (<-chan *DiscoveryEvent, error) {
+
+	watcher := &memoryDiscoveryWatcher{signal: make(chan struct{}, 1)}
+
+	c.mtx.Lock()
+	if c.watchers == nil {
+		c.watchers = make(map[string]map[*memoryDiscoveryWatcher]bool)
+	}
+	if c.watchers[key] == nil {
+		c.watchers[key] = make(map[*memoryDiscoveryWatcher]bool)
+	}
+	c.watchers[key][watcher] = true
+	c.scheduleExpiry()
+	c.mtx.Unlock()
+
+	events := make(chan *DiscoveryEvent, 1)
+
+	go func() {
+		defer close(events)
+		defer c.removeWatcher(key, watcher)
+
+		var last []*ConnectionParams
+		first := true
+		for {
+			current, _ := c.ResolveAll(ctx, correlationId, key)
+			added, removed := diffConnections(last, current)
+			if first || len(added) > 0 || len(removed) > 0 {
+				event := &DiscoveryEvent{
+					Key:         key,
+					Connections: current,
+					Added:       added,
+					Removed:     removed,
+				}
+				select {
+				case events <- event:
+				case <-ctx.Done():
+					return
+				}
+				last = current
+				first = false
+			}
+
+			select {
+			case <-watcher.signal:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return events, nil
+}
+
+func (c *MemoryDiscovery) removeWatcher(key string, watcher *memoryDiscoveryWatcher) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	delete(c.watchers[key], watcher)
+	if len(c.watchers[key]) == 0 {
+		delete(c.watchers, key)
+	}
+	c.scheduleExpiry()
+}
+
+// notifyWatchers signals watchers of the key. It must be called under the lock.
+func (c *MemoryDiscovery) notifyWatchers(key string) {
+	for watcher := range c.watchers[key] {
+		watcher.notify()
+	}
+}
+
+// scheduleExpiry sets a timer to remove expired registrations of watched keys,
+// so watchers learn about expired connections without other calls.
+// It must be called under the write lock.
+func (c *MemoryDiscovery) scheduleExpiry() {
+	if c.expiryTimer != nil {
+		c.expiryTimer.Stop()
+		c.expiryTimer = nil
+	}
+
+	var earliest time.Time
+	for key := range c.watchers {
+		for _, entry := range c.items[key] {
+			if !entry.expiresAt.IsZero() && (earliest.IsZero() || entry.expiresAt.Before(earliest)) {
+				earliest = entry.expiresAt
+			}
+		}
+	}
+	if earliest.IsZero() {
+		return
+	}
+
+	c.expiryTimer = time.AfterFunc(time.Until(earliest), func() {
+		c.mtx.Lock()
+		defer c.mtx.Unlock()
+
+		c.removeExpired(time.Now())
+		c.scheduleExpiry()
+	})
+}
@@ -7,8 +7,11 @@
 // Discovery:
 // Service that store a registry of various end-points (what services are where, and how to connect to them).
 // It knows the end-points, but doesn't have the credentials to connect to them. Separated for security reasons.
-//	IDiscovery – interface for creating registries.
+//	IContextDiscovery – interface for registries that accept context and support unregistration.
+//	IDiscovery – legacy interface for registries without context, adapted by DiscoveryContextAdapter.
 //	MemoryDiscovery – registry that is stored in memory.
+//	FileDiscovery – registry that is read from a shared JSON or YAML file.
+//	DnsDiscovery – registry that resolves connections from DNS SRV and A records.
 //
 //	There exist 2 types of discovery:
 //		Static discovery: all services have static IP addresses (like DNS, which also works using static discovery)
@@ -3,6 +3,7 @@ module github.com/pip-services3-gox/pip-services3-components-gox
 go 1.18
 
 require (
+	github.com/BurntSushi/toml v1.2.1
 	github.com/pip-services3-gox/pip-services3-commons-gox v1.0.8
 	github.com/pip-services3-gox/pip-services3-expressions-gox v1.0.2
 	github.com/stretchr/testify v1.8.0
@@ -0,0 +1,28 @@
+package state
+
+import "context"
+
+// ITransactionalStateStore interface for state storages that are able to update
+// several related states atomically. Either all the changes in a batch are applied
+// or none of them.
+type ITransactionalStateStore[T any] interface {
+	IStateStore[T]
+
+	// SaveMany saves multiple states into the store in a single atomic operation.
+	// If any of the states cannot be saved, the store remains unchanged.
+	//	Parameters:
+	//		- ctx context.Context
+	//		- correlationId (optional) transaction id to trace execution through call chain.
+	//		- values        states with their unique keys to be saved.
+	//	Returns: the states that were stored in the store or error.
+	SaveMany(ctx context.Context, correlationId string, values []StateValue[T]) ([]StateValue[T], error)
+
+	// DeleteMany deletes multiple states from the store in a single atomic operation.
+	// If any of the states cannot be deleted, the store remains unchanged.
+	//	Parameters:
+	//		- ctx context.Context
+	//		- correlationId (optional) transaction id to trace execution through call chain.
+	//		- keys          unique state keys.
+	//	Returns: the states that were deleted from the store or error.
+	DeleteMany(ctx context.Context, correlationId string, keys []string) ([]StateValue[T], error)
+}
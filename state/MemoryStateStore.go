@@ -183,3 +183,88 @@ func (c *MemoryStateStore[T]) Delete(ctx context.Context, correlationId string,
 
 	return defaultValue
 }
+
+// SaveMany saves multiple states into the store in a single atomic operation.
+// All keys are validated and all values are serialized before any change is applied,
+// so if one of the states cannot be saved the store remains unchanged.
+//	Parameters:
+//		- ctx context.Context
+//		- correlationId (optional) transaction id to trace execution through call chain.
+//		- values        states with their unique keys to be saved.
+//	Returns: the states that were stored in the store or error.
+func (c *MemoryStateStore[T]) SaveMany(ctx context.Context, correlationId string,
+	values []StateValue[T]) ([]StateValue[T], error) {
+
+	buffers := make([]string, len(values))
+	for i, value := range values {
+		if len(value.Key) == 0 {
+			return nil, errors.NewBadRequestError(correlationId, "EMPTY_KEY", "Key cannot be empty")
+		}
+
+		buf, err := c.convertor.ToJson(value.Value)
+		if err != nil {
+			return nil, errors.NewBadRequestError(
+				correlationId,
+				"INVALID_STATE",
+				"Failed to serialize state "+value.Key+": "+err.Error(),
+			).WithDetails("key", value.Key).WithCause(err)
+		}
+		buffers[i] = buf
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	// Cleanup the stored states
+	c.cleanup()
+
+	result := make([]StateValue[T], 0, len(values))
+	for i, value := range values {
+		if entry, ok := c.states[value.Key]; ok && entry != nil {
+			entry.SetValue(buffers[i])
+		} else {
+			c.states[value.Key] = NewStateEntry[string](value.Key, buffers[i])
+		}
+		result = append(result, value)
+	}
+
+	return result, nil
+}
+
+// DeleteMany deletes multiple states from the store in a single atomic operation.
+// All keys are validated before any state is removed,
+// so if one of the keys is invalid the store remains unchanged.
+//	Parameters:
+//		- ctx context.Context
+//		- correlationId (optional) transaction id to trace execution through call chain.
+//		- keys          unique state keys.
+//	Returns: the states that were deleted from the store or error.
+func (c *MemoryStateStore[T]) DeleteMany(ctx context.Context, correlationId string,
+	keys []string) ([]StateValue[T], error) {
+
+	for _, key := range keys {
+		if len(key) == 0 {
+			return nil, errors.NewBadRequestError(correlationId, "EMPTY_KEY", "Key cannot be empty")
+		}
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	// Cleanup the stored states
+	c.cleanup()
+
+	result := make([]StateValue[T], 0, len(keys))
+	for _, key := range keys {
+		if entry, ok := c.states[key]; ok {
+			delete(c.states, key)
+			if entry != nil {
+				if res, err := c.convertor.FromJson(entry.GetValue()); err == nil {
+					result = append(result, StateValue[T]{Key: key, Value: res})
+				}
+			}
+		}
+	}
+
+	return result, nil
+}
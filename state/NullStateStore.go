@@ -55,3 +55,25 @@ func (c *NullStateStore[T]) Delete(ctx context.Context, correlationId string, ke
 	var defaultValue T
 	return defaultValue
 }
+
+// SaveMany saves multiple states into the store in a single atomic operation.
+//	Parameters:
+//		- ctx context.Context
+//		- correlationId (optional) transaction id to trace execution through call chain.
+//		- values        states with their unique keys to be saved.
+//	Returns: the states that were stored in the store or error.
+func (c *NullStateStore[T]) SaveMany(ctx context.Context, correlationId string,
+	values []StateValue[T]) ([]StateValue[T], error) {
+	return values, nil
+}
+
+// DeleteMany deletes multiple states from the store in a single atomic operation.
+//	Parameters:
+//		- ctx context.Context
+//		- correlationId (optional) transaction id to trace execution through call chain.
+//		- keys          unique state keys.
+//	Returns: the states that were deleted from the store or error.
+func (c *NullStateStore[T]) DeleteMany(ctx context.Context, correlationId string,
+	keys []string) ([]StateValue[T], error) {
+	return []StateValue[T]{}, nil
+}
@@ -39,3 +39,14 @@ func TestGetAndSetAccessKey(t *testing.T) {
 	сredential.SetAccessKey("key")
 	assert.Equal(t, "key", сredential.AccessKey())
 }
+
+func TestGetAndSetSslFiles(t *testing.T) {
+	credential := auth.NewEmptyCredentialParams()
+	assert.Equal(t, "", credential.SslCertFile())
+	assert.Equal(t, "", credential.SslKeyFile())
+
+	credential.SetSslCertFile("client.pem")
+	credential.SetSslKeyFile("client-key.pem")
+	assert.Equal(t, "client.pem", credential.SslCertFile())
+	assert.Equal(t, "client-key.pem", credential.SslKeyFile())
+}
@@ -2,9 +2,13 @@ package test_auth
 
 import (
 	"context"
+	"strconv"
+	"sync"
 	"testing"
+	"time"
 
 	cconfig "github.com/pip-services3-gox/pip-services3-commons-gox/config"
+	crun "github.com/pip-services3-gox/pip-services3-commons-gox/run"
 	"github.com/pip-services3-gox/pip-services3-components-gox/auth"
 	"github.com/stretchr/testify/assert"
 )
@@ -42,3 +46,174 @@ func TestLookupAndStore(t *testing.T) {
 	assert.Equal(t, "pass3", cred3.Password())
 	assert.Equal(t, "12345", cred3.AccessId())
 }
+
+type credentialChangeListener struct {
+	changes []*auth.CredentialChange
+	mtx     sync.Mutex
+}
+
+func (c *credentialChangeListener) Notify(ctx context.Context, correlationId string, args *crun.Parameters) {
+	if change, ok := auth.CredentialChangeFromArgs(args); ok {
+		c.mtx.Lock()
+		c.changes = append(c.changes, change)
+		c.mtx.Unlock()
+	}
+}
+
+func TestCredentialRotation(t *testing.T) {
+	credentialStore := auth.NewMemoryCredentialStore(context.Background(), cconfig.NewConfigParamsFromTuples(
+		"key1.user", "user1",
+		"key1.pass", "pass1",
+	))
+	assert.Equal(t, 1, credentialStore.Version("key1"))
+	assert.Equal(t, 0, credentialStore.Version("key2"))
+
+	listener := &credentialChangeListener{}
+	credentialStore.AddChangeListener(context.Background(), listener)
+
+	// Storing the same credential is not a rotation
+	err := credentialStore.Store(context.Background(), "123", "key1",
+		auth.NewCredentialParamsFromTuples("user", "user1", "pass", "pass1"))
+	assert.Nil(t, err)
+	assert.Equal(t, 1, credentialStore.Version("key1"))
+	assert.Len(t, listener.changes, 0)
+
+	err = credentialStore.Store(context.Background(), "123", "key1",
+		auth.NewCredentialParamsFromTuples("user", "user1", "pass", "pass2"))
+	assert.Nil(t, err)
+	assert.Equal(t, 2, credentialStore.Version("key1"))
+
+	active, err := credentialStore.Lookup(context.Background(), "123", "key1")
+	assert.Nil(t, err)
+	assert.Equal(t, "pass2", active.Password())
+	previous, err := credentialStore.LookupPrevious(context.Background(), "123", "key1")
+	assert.Nil(t, err)
+	assert.Equal(t, "pass1", previous.Password())
+
+	assert.Len(t, listener.changes, 1)
+	assert.Equal(t, "key1", listener.changes[0].Key)
+	assert.Equal(t, 2, listener.changes[0].Version)
+	assert.Equal(t, "pass2", listener.changes[0].Credential.Password())
+	assert.Equal(t, "pass1", listener.changes[0].Previous.Password())
+
+	credentialStore.ExpirePrevious(context.Background(), "123", "key1")
+	previous, err = credentialStore.LookupPrevious(context.Background(), "123", "key1")
+	assert.Nil(t, err)
+	assert.Nil(t, previous)
+
+	err = credentialStore.Store(context.Background(), "123", "key1", nil)
+	assert.Nil(t, err)
+	assert.Len(t, listener.changes, 2)
+	assert.True(t, listener.changes[1].IsRemoved())
+	assert.Equal(t, 3, listener.changes[1].Version)
+	_, err = credentialStore.Lookup(context.Background(), "123", "key1")
+	assert.NotNil(t, err)
+	_, err = credentialStore.LookupPrevious(context.Background(), "123", "key1")
+	assert.NotNil(t, err)
+
+	credentialStore.RemoveChangeListener(context.Background(), listener)
+	err = credentialStore.Store(context.Background(), "123", "key1", auth.NewCredentialParamsFromTuples("user", "user1"))
+	assert.Nil(t, err)
+	assert.Len(t, listener.changes, 2)
+
+	// Versions don't go backwards after removal
+	assert.Equal(t, 4, credentialStore.Version("key1"))
+}
+
+func TestCredentialStoreReturnsCopies(t *testing.T) {
+	credentialStore := auth.NewEmptyMemoryCredentialStore()
+	credential := auth.NewCredentialParamsFromTuples("user", "user1", "pass", "pass1")
+	_ = credentialStore.Store(context.Background(), "123", "key1", credential)
+	_ = credentialStore.Store(context.Background(), "123", "key1",
+		auth.NewCredentialParamsFromTuples("user", "user1", "pass", "pass2"))
+
+	credential.SetPassword("changed")
+	active, _ := credentialStore.Lookup(context.Background(), "123", "key1")
+	active.SetPassword("changed")
+	previous, _ := credentialStore.LookupPrevious(context.Background(), "123", "key1")
+	previous.SetPassword("changed")
+
+	active, _ = credentialStore.Lookup(context.Background(), "123", "key1")
+	assert.Equal(t, "pass2", active.Password())
+	previous, _ = credentialStore.LookupPrevious(context.Background(), "123", "key1")
+	assert.Equal(t, "pass1", previous.Password())
+	assert.Equal(t, 2, credentialStore.Version("key1"))
+}
+
+func TestCredentialStoreConcurrency(t *testing.T) {
+	credentialStore := auth.NewEmptyMemoryCredentialStore()
+	listener := &credentialChangeListener{}
+	credentialStore.AddChangeListener(context.Background(), listener)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				credential := auth.NewCredentialParamsFromTuples("user", "user", "pass", strconv.Itoa(i*100+j))
+				_ = credentialStore.Store(context.Background(), "123", "key", credential)
+				_, _ = credentialStore.Lookup(context.Background(), "123", "key")
+				_, _ = credentialStore.LookupPrevious(context.Background(), "123", "key")
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	assert.Equal(t, 1000, credentialStore.Version("key"))
+	assert.Len(t, listener.changes, 1000)
+
+	// Notifications are delivered in the order of versions
+	for i, change := range listener.changes {
+		assert.Equal(t, i+1, change.Version)
+	}
+}
+
+// refreshingListener changes the store when it is notified.
+type refreshingListener struct {
+	store   *auth.MemoryCredentialStore
+	changes []*auth.CredentialChange
+}
+
+func (c *refreshingListener) Notify(ctx context.Context, correlationId string, args *crun.Parameters) {
+	change, ok := auth.CredentialChangeFromArgs(args)
+	if !ok {
+		return
+	}
+	c.changes = append(c.changes, change)
+
+	if change.Key == "key1" && change.Version == 1 {
+		_, _ = c.store.Lookup(ctx, correlationId, "key1")
+		_ = c.store.Store(ctx, correlationId, "key2", auth.NewCredentialParamsFromTuples("user", "user2"))
+		c.store.ReadCredentials(cconfig.NewConfigParamsFromTuples(
+			"key1.user", "user1",
+			"key2.user", "user3",
+		))
+	}
+}
+
+func TestCredentialStoreReentrantListener(t *testing.T) {
+	credentialStore := auth.NewEmptyMemoryCredentialStore()
+	listener := &refreshingListener{store: credentialStore}
+	credentialStore.AddChangeListener(context.Background(), listener)
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = credentialStore.Store(context.Background(), "123", "key1", auth.NewCredentialParamsFromTuples("user", "user1"))
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("listener changing the store deadlocked")
+	}
+
+	assert.Len(t, listener.changes, 3)
+	assert.Equal(t, "key1", listener.changes[0].Key)
+	assert.Equal(t, "key2", listener.changes[1].Key)
+	assert.Equal(t, 1, listener.changes[1].Version)
+	assert.Equal(t, "key2", listener.changes[2].Key)
+	assert.Equal(t, 2, listener.changes[2].Version)
+	assert.Equal(t, "user3", listener.changes[2].Credential.Username())
+}
@@ -0,0 +1,72 @@
+package test_config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	cconfig "github.com/pip-services3-gox/pip-services3-commons-gox/config"
+	"github.com/pip-services3-gox/pip-services3-components-gox/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReadConfigByExtension(t *testing.T) {
+	parameters := cconfig.NewConfigParamsFromTuples(
+		"param1", "Test Param 1",
+		"param2", "Test Param 2",
+	)
+
+	for _, path := range []string{"./config.json", "./config.yml", "./config.toml", "./config.properties"} {
+		config, err := config.ReadConfig(context.Background(), "", path, parameters)
+
+		assert.Nil(t, err, path)
+		assert.Equal(t, 123, config.GetAsInteger("field1.field11"), path)
+		assert.Equal(t, "Test Param 1", config.GetAsString("field4"), path)
+	}
+
+	_, err := config.ReadConfig(context.Background(), "", "./config.xml", parameters)
+	assert.NotNil(t, err)
+}
+
+func TestReadConfigWithRegisteredExtension(t *testing.T) {
+	config.RegisterConfigReaderExtension("conf", func(path string) config.IConfigReader {
+		return config.NewYamlConfigReader(path)
+	})
+	defer config.RegisterConfigReaderExtension("conf", nil)
+
+	path := filepath.Join(t.TempDir(), "service.conf")
+	assert.Nil(t, os.WriteFile(path, []byte("key1: A\n"), 0644))
+
+	config, err := config.ReadConfig(context.Background(), "", path, nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "A", config.GetAsString("key1"))
+}
+
+func TestAutoFileConfigReader(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.toml")
+	assert.Nil(t, os.WriteFile(path, []byte("key1 = \"A\"\n"), 0644))
+
+	reader := config.NewEmptyAutoFileConfigReader()
+	reader.Configure(context.Background(), cconfig.NewConfigParamsFromTuples(
+		"path", path,
+		"options.check_interval", 10,
+	))
+
+	listener := newChangeListener()
+	reader.AddChangeListener(context.Background(), listener)
+	defer reader.RemoveChangeListener(context.Background(), listener)
+
+	config, err := reader.ReadConfig(context.Background(), "123", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "A", config.GetAsString("key1"))
+
+	assert.Nil(t, os.WriteFile(path, []byte("key1 = \"B\"\n"), 0644))
+	_, ok := listener.wait(time.Second)
+	assert.True(t, ok)
+
+	config, err = reader.ReadConfig(context.Background(), "123", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "B", config.GetAsString("key1"))
+}
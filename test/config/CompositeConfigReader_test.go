@@ -0,0 +1,99 @@
+package test_config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	cconfig "github.com/pip-services3-gox/pip-services3-commons-gox/config"
+	"github.com/pip-services3-gox/pip-services3-commons-gox/refer"
+	"github.com/pip-services3-gox/pip-services3-components-gox/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompositeConfigReaderMergesLayers(t *testing.T) {
+	t.Setenv("APP__CONNECTION__PORT", "8082")
+
+	reader := config.NewCompositeConfigReader(
+		config.NewJsonConfigReader("./config.json"),
+		config.NewMemoryConfigReader(cconfig.NewConfigParamsFromTuples(
+			"connection.host", "localhost",
+			"connection.port", "8080",
+			"field3", false,
+		)),
+		config.NewEnvConfigReader("APP"),
+	)
+
+	parameters := cconfig.NewConfigParamsFromTuples(
+		"param1", "Test Param 1",
+		"param2", "Test Param 2",
+	)
+	config, err := reader.ReadConfig(context.Background(), "123", parameters)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 123, config.GetAsInteger("field1.field11"))
+	assert.Equal(t, "Test Param 1", config.GetAsString("field4"))
+	assert.Equal(t, false, config.GetAsBoolean("field3"))
+	assert.Equal(t, "localhost", config.GetAsString("connection.host"))
+	assert.Equal(t, 8082, config.GetAsInteger("connection.port"))
+}
+
+func TestCompositeConfigReaderPropagatesChanges(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yml")
+	assert.Nil(t, os.WriteFile(path, []byte("key1: A\n"), 0644))
+
+	fileReader := config.NewYamlConfigReader(path)
+	fileReader.SetCheckInterval(10)
+	reader := config.NewCompositeConfigReader(
+		config.NewMemoryConfigReader(cconfig.NewConfigParamsFromTuples("key1", "default")),
+		fileReader,
+	)
+
+	listener := newChangeListener()
+	reader.AddChangeListener(context.Background(), listener)
+	defer reader.RemoveChangeListener(context.Background(), listener)
+
+	assert.Nil(t, os.WriteFile(path, []byte("key1: B\n"), 0644))
+	_, ok := listener.wait(time.Second)
+	assert.True(t, ok)
+
+	config, err := reader.ReadConfig(context.Background(), "123", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "B", config.GetAsString("key1"))
+}
+
+func TestCompositeConfigReaderReferencesOrder(t *testing.T) {
+	defaults := config.NewMemoryConfigReader(cconfig.NewConfigParamsFromTuples("key1", "defaults", "key2", "defaults"))
+	overrides := config.NewMemoryConfigReader(cconfig.NewConfigParamsFromTuples("key1", "overrides"))
+
+	// Readers are registered in the opposite order of their precedence
+	references := refer.NewReferencesFromTuples(context.Background(),
+		refer.NewDescriptor("pip-services", "config-reader", "memory", "overrides", "1.0"), overrides,
+		refer.NewDescriptor("pip-services", "config-reader", "memory", "defaults", "1.0"), defaults,
+	)
+
+	reader := config.NewEmptyCompositeConfigReader()
+	reader.Configure(context.Background(), cconfig.NewConfigParamsFromTuples(
+		"readers.0", "*:config-reader:*:defaults:*",
+		"readers.1", "*:config-reader:*:overrides:*",
+	))
+	reader.SetReferences(context.Background(), references)
+	conf, err := reader.ReadConfig(context.Background(), "", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "overrides", conf.GetAsString("key1"))
+	assert.Equal(t, "defaults", conf.GetAsString("key2"))
+
+	// Without configured order readers are sorted by their descriptors
+	reader = config.NewEmptyCompositeConfigReader()
+	reader.SetReferences(context.Background(), references)
+	assert.Equal(t, []config.IConfigReader{defaults, overrides}, reader.Readers())
+
+	reader = config.NewEmptyCompositeConfigReader()
+	reader.Configure(context.Background(), cconfig.NewConfigParamsFromTuples(
+		"readers.0", "config-reader:memory",
+	))
+	_, err = reader.ReadConfig(context.Background(), "", nil)
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,158 @@
+package test_config
+
+import (
+	"net"
+	"testing"
+	"time"
+
+	cconfig "github.com/pip-services3-gox/pip-services3-commons-gox/config"
+	"github.com/pip-services3-gox/pip-services3-commons-gox/errors"
+	"github.com/pip-services3-gox/pip-services3-components-gox/config"
+	"github.com/stretchr/testify/assert"
+)
+
+type testConnectionConfig struct {
+	Host    string        `config:"host,required"`
+	Port    int           `config:"port" default:"8080"`
+	Timeout time.Duration `config:"timeout" default:"30s"`
+}
+
+type testServiceConfig struct {
+	Connection testConnectionConfig  `config:"connection"`
+	Backup     *testConnectionConfig `config:"backup"`
+	MaxRetries int
+	Enabled    bool              `default:"true"`
+	Ratio      float64           `config:"options.ratio"`
+	Hosts      []string          `config:"hosts"`
+	Tags       []string          `config:"tags"`
+	Labels     map[string]string `config:"labels"`
+	Interval   time.Duration     `config:"interval"`
+	Ignored    string            `config:"-"`
+}
+
+func TestBindConfig(t *testing.T) {
+	conf := cconfig.NewConfigParamsFromTuples(
+		"connection.host", "localhost",
+		"connection.timeout", "1m",
+		"max_retries", 5,
+		"options.ratio", 0.5,
+		"hosts.0", "host1",
+		"hosts.1", "host2",
+		"tags", "a, b",
+		"labels.env", "prod",
+		"labels.zone", "us",
+		"interval", 1500,
+		"ignored", "value",
+	)
+
+	var target testServiceConfig
+	target.Ignored = "keep"
+	err := config.BindConfig("123", conf, &target)
+	assert.Nil(t, err)
+
+	assert.Equal(t, "localhost", target.Connection.Host)
+	assert.Equal(t, 8080, target.Connection.Port)
+	assert.Equal(t, time.Minute, target.Connection.Timeout)
+	assert.Nil(t, target.Backup)
+	assert.Equal(t, 5, target.MaxRetries)
+	assert.True(t, target.Enabled)
+	assert.Equal(t, 0.5, target.Ratio)
+	assert.Equal(t, []string{"host1", "host2"}, target.Hosts)
+	assert.Equal(t, []string{"a", "b"}, target.Tags)
+	assert.Equal(t, map[string]string{"env": "prod", "zone": "us"}, target.Labels)
+	assert.Equal(t, 1500*time.Millisecond, target.Interval)
+	assert.Equal(t, "keep", target.Ignored)
+}
+
+func TestBindConfigNestedPointer(t *testing.T) {
+	conf := cconfig.NewConfigParamsFromTuples(
+		"connection.host", "localhost",
+		"backup.host", "backup",
+		"backup.port", 9090,
+	)
+
+	var target testServiceConfig
+	err := config.BindConfig("123", conf, &target)
+	assert.Nil(t, err)
+	assert.NotNil(t, target.Backup)
+	assert.Equal(t, "backup", target.Backup.Host)
+	assert.Equal(t, 9090, target.Backup.Port)
+	assert.Equal(t, 30*time.Second, target.Backup.Timeout)
+}
+
+func TestBindConfigAggregatesErrors(t *testing.T) {
+	conf := cconfig.NewConfigParamsFromTuples(
+		"max_retries", "many",
+		"interval", "soon",
+	)
+
+	var target testServiceConfig
+	err := config.BindConfig("123", conf, &target)
+	assert.NotNil(t, err)
+
+	appErr, ok := err.(*errors.ApplicationError)
+	assert.True(t, ok)
+	assert.Equal(t, "BIND_FAILED", appErr.Code)
+	assert.Contains(t, appErr.Message, "connection.host is required")
+	assert.Contains(t, appErr.Message, "max_retries")
+	assert.Contains(t, appErr.Message, "interval")
+
+	err = config.BindConfig("123", conf, target)
+	assert.NotNil(t, err)
+}
+
+type testBaseConfig struct {
+	Name    string `config:"name,required"`
+	Retries int    `default:"3"`
+}
+
+type TestEndpointConfig struct {
+	Host string `config:"host,required"`
+}
+
+type testTimedConfig struct {
+	testBaseConfig
+	*TestEndpointConfig `config:"endpoint"`
+	Started             time.Time
+	Expires             *time.Time
+	Address             net.IP
+	Schedule            []time.Time
+}
+
+func TestBindConfigValueTypes(t *testing.T) {
+	conf := cconfig.NewConfigParamsFromTuples(
+		"name", "timed",
+		"started", "2024-01-02T15:04:05Z",
+		"expires", "2024-02-03",
+		"address", "10.0.0.1",
+		"schedule", "2024-01-01T00:00:00Z,2024-01-02T00:00:00Z",
+		"endpoint.host", "localhost",
+	)
+
+	var target testTimedConfig
+	err := config.BindConfig("123", conf, &target)
+	assert.Nil(t, err)
+	assert.Equal(t, "timed", target.Name)
+	assert.Equal(t, 3, target.Retries)
+	assert.Equal(t, time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC), target.Started)
+	assert.NotNil(t, target.Expires)
+	assert.Equal(t, time.Date(2024, 2, 3, 0, 0, 0, 0, time.UTC), *target.Expires)
+	assert.Equal(t, "10.0.0.1", target.Address.String())
+	assert.Len(t, target.Schedule, 2)
+	assert.NotNil(t, target.TestEndpointConfig)
+	assert.Equal(t, "localhost", target.TestEndpointConfig.Host)
+
+	conf = cconfig.NewConfigParamsFromTuples(
+		"started", "yesterday",
+		"address", "not an ip",
+		"endpoint.host", "localhost",
+	)
+	err = config.BindConfig("123", conf, &testTimedConfig{})
+	assert.NotNil(t, err)
+
+	appErr, ok := err.(*errors.ApplicationError)
+	assert.True(t, ok)
+	assert.Contains(t, appErr.Message, "name is required")
+	assert.Contains(t, appErr.Message, "started")
+	assert.Contains(t, appErr.Message, "address")
+}
@@ -0,0 +1,160 @@
+package test_config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	cconfig "github.com/pip-services3-gox/pip-services3-commons-gox/config"
+	crun "github.com/pip-services3-gox/pip-services3-commons-gox/run"
+	"github.com/pip-services3-gox/pip-services3-components-gox/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffConfig(t *testing.T) {
+	oldConfig := cconfig.NewConfigParamsFromTuples(
+		"connection.host", "localhost",
+		"connection.port", "8080",
+		"options.timeout", "1000",
+	)
+	newConfig := cconfig.NewConfigParamsFromTuples(
+		"connection.host", "localhost",
+		"connection.port", "8081",
+		"logger.level", "debug",
+	)
+
+	change := config.DiffConfig(oldConfig, newConfig)
+	assert.False(t, change.IsEmpty())
+	assert.Equal(t, []string{"logger.level"}, change.Added)
+	assert.Equal(t, []string{"options.timeout"}, change.Removed)
+	assert.Equal(t, []string{"connection.port"}, change.Changed)
+	assert.Equal(t, []string{"connection", "logger", "options"}, change.Sections())
+	assert.True(t, change.HasSection("connection"))
+	assert.False(t, change.HasSection("conn"))
+	assert.True(t, change.HasKey("options.timeout"))
+	assert.False(t, change.HasKey("connection.host"))
+
+	change = config.DiffConfig(oldConfig, oldConfig)
+	assert.True(t, change.IsEmpty())
+}
+
+func TestConfigReloaderNotifiesWithDiff(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	assert.Nil(t, os.WriteFile(path, []byte(`{ "connection": { "host": "localhost", "port": 8080 } }`), 0644))
+
+	reader := config.NewJsonConfigReader(path)
+	reader.Configure(context.Background(), cconfig.NewConfigParamsFromTuples(
+		"options.check_interval", 10,
+	))
+
+	reloader := config.NewConfigReloader(reader, nil)
+	listener := newChangeListener()
+	reloader.AddChangeListener(context.Background(), listener)
+
+	assert.Nil(t, reloader.Open(context.Background(), ""))
+	defer reloader.Close(context.Background(), "")
+	assert.Equal(t, 8080, reloader.Config().GetAsInteger("connection.port"))
+
+	assert.Nil(t, os.WriteFile(path, []byte(`{ "connection": { "host": "localhost", "port": 8081 }, "options": { "retries": 3 } }`), 0644))
+
+	args, ok := listener.wait(5 * time.Second)
+	assert.True(t, ok)
+	change, ok := config.ConfigChangeFromArgs(args)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"options.retries"}, change.Added)
+	assert.Equal(t, []string{"connection.port"}, change.Changed)
+	assert.Len(t, change.Removed, 0)
+	assert.Equal(t, 8081, reloader.Config().GetAsInteger("connection.port"))
+
+	// Reloading the same content doesn't notify listeners
+	change, err := reloader.Reload(context.Background(), "")
+	assert.Nil(t, err)
+	assert.True(t, change.IsEmpty())
+	_, ok = listener.wait(50 * time.Millisecond)
+	assert.False(t, ok)
+}
+
+func TestConfigReaderListenersReceiveDiff(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	assert.Nil(t, os.WriteFile(path, []byte(`{ "connection": { "host": "{{HOST}}", "port": 8080 } }`), 0644))
+
+	reader := config.NewJsonConfigReader(path)
+	reader.Configure(context.Background(), cconfig.NewConfigParamsFromTuples(
+		"options.check_interval", 10,
+	))
+	composite := config.NewCompositeConfigReader(
+		reader,
+		config.NewMemoryConfigReader(cconfig.NewConfigParamsFromTuples("connection.port", 9090)),
+	)
+
+	parameters := cconfig.NewConfigParamsFromTuples("HOST", "localhost")
+	_, err := reader.ReadConfig(context.Background(), "", parameters)
+	assert.Nil(t, err)
+	_, err = composite.ReadConfig(context.Background(), "", parameters)
+	assert.Nil(t, err)
+
+	readerListener := newChangeListener()
+	reader.AddChangeListener(context.Background(), readerListener)
+	defer reader.RemoveChangeListener(context.Background(), readerListener)
+	compositeListener := newChangeListener()
+	composite.AddChangeListener(context.Background(), compositeListener)
+	defer composite.RemoveChangeListener(context.Background(), compositeListener)
+
+	assert.Nil(t, os.WriteFile(path, []byte(`{ "connection": { "host": "{{HOST}}", "port": 8081 }, "options": { "retries": 3 } }`), 0644))
+
+	args, ok := readerListener.wait(5 * time.Second)
+	assert.True(t, ok)
+	change, ok := config.ConfigChangeFromArgs(args)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"options.retries"}, change.Added)
+	assert.Equal(t, []string{"connection.port"}, change.Changed)
+	assert.Equal(t, "localhost", change.NewConfig.GetAsString("connection.host"))
+
+	// The composite reader reports changes of the merged configuration
+	args, ok = compositeListener.wait(5 * time.Second)
+	assert.True(t, ok)
+	change, ok = config.ConfigChangeFromArgs(args)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"options.retries"}, change.Added)
+	assert.Len(t, change.Changed, 0)
+}
+
+// countingConfigReader counts configuration reads.
+type countingConfigReader struct {
+	*config.MemoryConfigReader
+	reads int
+}
+
+func (c *countingConfigReader) ReadConfig(ctx context.Context, correlationId string,
+	parameters *cconfig.ConfigParams) (*cconfig.ConfigParams, error) {
+
+	c.reads++
+	return c.MemoryConfigReader.ReadConfig(ctx, correlationId, parameters)
+}
+
+func TestConfigReloaderUsesReaderChange(t *testing.T) {
+	reader := &countingConfigReader{
+		MemoryConfigReader: config.NewMemoryConfigReader(cconfig.NewConfigParamsFromTuples("connection.port", 8080)),
+	}
+	reloader := config.NewConfigReloader(reader, nil)
+	listener := newChangeListener()
+	reloader.AddChangeListener(context.Background(), listener)
+
+	assert.Nil(t, reloader.Open(context.Background(), ""))
+	defer reloader.Close(context.Background(), "")
+	assert.Equal(t, 1, reader.reads)
+
+	newConfig := cconfig.NewConfigParamsFromTuples("connection.port", 8081)
+	readerChange := config.DiffConfig(reloader.Config(), newConfig)
+	reloader.Notify(context.Background(), "", crun.NewParametersFromTuples(config.ConfigChangeArgKey, readerChange))
+
+	args, ok := listener.wait(time.Second)
+	assert.True(t, ok)
+	change, ok := config.ConfigChangeFromArgs(args)
+	assert.True(t, ok)
+	assert.Equal(t, []string{"connection.port"}, change.Changed)
+	assert.Equal(t, 8081, reloader.Config().GetAsInteger("connection.port"))
+	assert.Equal(t, 1, reader.reads)
+}
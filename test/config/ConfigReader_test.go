@@ -0,0 +1,71 @@
+package test_config
+
+import (
+	"testing"
+
+	cconfig "github.com/pip-services3-gox/pip-services3-commons-gox/config"
+	"github.com/pip-services3-gox/pip-services3-components-gox/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigReaderParameterDefaults(t *testing.T) {
+	reader := config.NewConfigReader()
+
+	result, err := reader.Parameterize(
+		"uri=mongodb://{{DB_HOST:localhost}}:{{DB_PORT:27017}}/{{DB_NAME}}",
+		cconfig.NewConfigParamsFromTuples("DB_PORT", 27018, "DB_NAME", "test"),
+	)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "uri=mongodb://localhost:27018/test", result)
+}
+
+func TestConfigReaderStrictParameters(t *testing.T) {
+	reader := config.NewConfigReader()
+	reader.SetStrict(true)
+
+	_, err := reader.Parameterize(
+		"host={{DB_HOST}};port={{DB_PORT:27017}};{{#USE_SSL}}ca={{SSL_CA}}{{/USE_SSL}};user={{DB_USER}}",
+		cconfig.NewConfigParamsFromTuples("DB_USER", "admin"),
+	)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "DB_HOST")
+	assert.NotContains(t, err.Error(), "DB_PORT")
+	assert.NotContains(t, err.Error(), "SSL_CA")
+
+	_, err = reader.Parameterize(
+		"host={{DB_HOST}};{{#USE_SSL}}ca={{SSL_CA}}{{/USE_SSL}}",
+		cconfig.NewConfigParamsFromTuples("DB_HOST", "localhost", "USE_SSL", true),
+	)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "SSL_CA")
+}
+
+func TestConfigReaderEnvParameters(t *testing.T) {
+	t.Setenv("TEST_DB_HOST", "10.1.1.100")
+
+	reader := config.NewConfigReader()
+	result, err := reader.Parameterize("host={{TEST_DB_HOST}}", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "host=", result)
+
+	reader.SetEnvParameters(true)
+	result, err = reader.Parameterize(
+		"host={{TEST_DB_HOST}};port={{TEST_DB_PORT}}",
+		cconfig.NewConfigParamsFromTuples("TEST_DB_PORT", 27017),
+	)
+	assert.Nil(t, err)
+	assert.Equal(t, "host=10.1.1.100;port=27017", result)
+
+	result, err = reader.Parameterize(
+		"host={{TEST_DB_HOST}}",
+		cconfig.NewConfigParamsFromTuples("TEST_DB_HOST", "localhost"),
+	)
+	assert.Nil(t, err)
+	assert.Equal(t, "host=localhost", result)
+
+	reader.SetEnvParameters(false)
+	result, err = reader.Parameterize("host={{TEST_DB_HOST}}", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "host=", result)
+}
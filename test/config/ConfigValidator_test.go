@@ -0,0 +1,58 @@
+package test_config
+
+import (
+	"context"
+	"testing"
+
+	cconfig "github.com/pip-services3-gox/pip-services3-commons-gox/config"
+	"github.com/pip-services3-gox/pip-services3-commons-gox/convert"
+	cerr "github.com/pip-services3-gox/pip-services3-commons-gox/errors"
+	"github.com/pip-services3-gox/pip-services3-commons-gox/validate"
+	"github.com/pip-services3-gox/pip-services3-components-gox/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestConfigSchema() validate.ISchema {
+	return validate.NewObjectSchema().
+		WithRequiredProperty("connection", validate.NewObjectSchema().
+			WithRequiredProperty("host", convert.String).
+			WithRequiredProperty("port", convert.Integer),
+		).
+		WithRequiredProperty("credential", validate.NewObjectSchema().
+			WithRequiredProperty("username", convert.String).
+			WithOptionalProperty("password", convert.String),
+		).
+		WithOptionalProperty("hosts", validate.NewArraySchema(convert.String))
+}
+
+func TestValidateConfig(t *testing.T) {
+	cfg := cconfig.NewConfigParamsFromTuples(
+		"connection.host", "localhost",
+		"connection.port", "8080",
+		"credential.username", "admin",
+		"credential.password", "12345",
+		"hosts.0", "host1",
+		"hosts.1", "host2",
+	)
+
+	err := config.ValidateConfig("123", cfg, newTestConfigSchema())
+	assert.Nil(t, err)
+}
+
+func TestConfigReaderFailsOnInvalidConfig(t *testing.T) {
+	reader := config.NewMemoryConfigReader(cconfig.NewConfigParamsFromTuples(
+		"connection.host", "localhost",
+		"connection.port", "abc",
+		"credential.password", "12345",
+	))
+	reader.SetSchema(newTestConfigSchema())
+
+	_, err := reader.ReadConfig(context.Background(), "123", nil)
+
+	assert.NotNil(t, err)
+	appErr, ok := err.(*cerr.ApplicationError)
+	assert.True(t, ok)
+	assert.Equal(t, cerr.Misconfiguration, appErr.Category)
+	assert.Contains(t, appErr.Message, "connection.port")
+	assert.Contains(t, appErr.Message, "credential.username")
+}
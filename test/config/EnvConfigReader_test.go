@@ -0,0 +1,58 @@
+package test_config
+
+import (
+	"context"
+	"testing"
+
+	cconfig "github.com/pip-services3-gox/pip-services3-commons-gox/config"
+	"github.com/pip-services3-gox/pip-services3-components-gox/config"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEnvConfigReader(t *testing.T) {
+	t.Setenv("APP__CONNECTION__HOST", "10.1.1.100")
+	t.Setenv("APP__CONNECTION__PORT", "8080")
+	t.Setenv("APP__OPTIONS__NAME", "{{param1}}")
+	t.Setenv("OTHER__CONNECTION__HOST", "10.1.1.101")
+
+	parameters := cconfig.NewConfigParamsFromTuples(
+		"param1", "Test Param 1",
+	)
+	config, err := config.ReadEnvConfig(context.Background(), "", "APP", parameters)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 3, config.Len())
+	assert.Equal(t, "10.1.1.100", config.GetAsString("connection.host"))
+	assert.Equal(t, 8080, config.GetAsInteger("connection.port"))
+	assert.Equal(t, "Test Param 1", config.GetAsString("options.name"))
+}
+
+func TestEnvConfigReaderCustomSeparator(t *testing.T) {
+	t.Setenv("SVC_CONNECTION_HOST", "10.1.1.100")
+
+	reader := config.NewEmptyEnvConfigReader()
+	reader.Configure(context.Background(), cconfig.NewConfigParamsFromTuples(
+		"prefix", "svc",
+		"separator", "_",
+	))
+	config, err := reader.ReadConfig(context.Background(), "", nil)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "10.1.1.100", config.GetAsString("connection.host"))
+}
+
+func TestEnvConfigReaderRequiresPrefix(t *testing.T) {
+	t.Setenv("ENV_READER_TEST", "value")
+
+	reader := config.NewEmptyEnvConfigReader()
+	_, err := reader.ReadConfig(context.Background(), "", nil)
+	assert.NotNil(t, err)
+
+	reader.Configure(context.Background(), cconfig.NewConfigParamsFromTuples(
+		"options.read_all", true,
+	))
+	assert.True(t, reader.ReadAll())
+	config, err := reader.ReadConfig(context.Background(), "", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "value", config.GetAsString("env_reader_test"))
+}
@@ -0,0 +1,107 @@
+package test_config
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	cconfig "github.com/pip-services3-gox/pip-services3-commons-gox/config"
+	crun "github.com/pip-services3-gox/pip-services3-commons-gox/run"
+	"github.com/pip-services3-gox/pip-services3-components-gox/config"
+	"github.com/stretchr/testify/assert"
+)
+
+type changeListener struct {
+	events chan *crun.Parameters
+}
+
+func newChangeListener() *changeListener {
+	return &changeListener{events: make(chan *crun.Parameters, 10)}
+}
+
+func (c *changeListener) Notify(ctx context.Context, correlationId string, args *crun.Parameters) {
+	c.events <- args
+}
+
+func (c *changeListener) wait(timeout time.Duration) (*crun.Parameters, bool) {
+	select {
+	case args := <-c.events:
+		return args, true
+	case <-time.After(timeout):
+		return nil, false
+	}
+}
+
+func TestFileConfigReaderNotifiesOnChange(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.json")
+	assert.Nil(t, os.WriteFile(path, []byte(`{ "key1": "A" }`), 0644))
+
+	reader := config.NewJsonConfigReader(path)
+	reader.Configure(context.Background(), cconfig.NewConfigParamsFromTuples(
+		"options.check_interval", 10,
+	))
+
+	listener := newChangeListener()
+	reader.AddChangeListener(context.Background(), listener)
+	defer reader.RemoveChangeListener(context.Background(), listener)
+
+	// Touching the file without changing its content is not a change
+	now := time.Now().Add(time.Second)
+	assert.Nil(t, os.Chtimes(path, now, now))
+	_, ok := listener.wait(100 * time.Millisecond)
+	assert.False(t, ok)
+
+	assert.Nil(t, os.WriteFile(path, []byte(`{ "key1": "B" }`), 0644))
+	args, ok := listener.wait(time.Second)
+	assert.True(t, ok)
+	assert.Equal(t, path, args.GetAsString("path"))
+
+	config, err := reader.ReadConfig(context.Background(), "123", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "B", config.GetAsString("key1"))
+}
+
+func TestFileConfigReaderStopsWatching(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yml")
+	assert.Nil(t, os.WriteFile(path, []byte("key1: A\n"), 0644))
+
+	reader := config.NewYamlConfigReader(path)
+	reader.SetCheckInterval(10)
+
+	listener := newChangeListener()
+	reader.AddChangeListener(context.Background(), listener)
+	reader.RemoveChangeListener(context.Background(), listener)
+
+	assert.Nil(t, os.WriteFile(path, []byte("key1: B\n"), 0644))
+	_, ok := listener.wait(100 * time.Millisecond)
+	assert.False(t, ok)
+}
+
+func TestFileConfigReaderWatchesIncludes(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yml")
+	includePath := filepath.Join(dir, "connection.yml")
+	assert.Nil(t, os.WriteFile(path, []byte("connection: !include connection.yml\n"), 0644))
+	assert.Nil(t, os.WriteFile(includePath, []byte("host: localhost\n"), 0644))
+
+	reader := config.NewYamlConfigReader(path)
+	reader.SetCheckInterval(10)
+	conf, err := reader.ReadConfig(context.Background(), "123", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "localhost", conf.GetAsString("connection.host"))
+
+	listener := newChangeListener()
+	reader.AddChangeListener(context.Background(), listener)
+	defer reader.RemoveChangeListener(context.Background(), listener)
+
+	assert.Nil(t, os.WriteFile(includePath, []byte("host: 10.1.1.100\n"), 0644))
+	args, ok := listener.wait(time.Second)
+	assert.True(t, ok)
+	assert.Equal(t, includePath, args.GetAsString("path"))
+
+	conf, err = reader.ReadConfig(context.Background(), "123", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "10.1.1.100", conf.GetAsString("connection.host"))
+}
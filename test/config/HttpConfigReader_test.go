@@ -0,0 +1,195 @@
+package test_config
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	cconfig "github.com/pip-services3-gox/pip-services3-commons-gox/config"
+	"github.com/pip-services3-gox/pip-services3-components-gox/config"
+	"github.com/stretchr/testify/assert"
+)
+
+type configServer struct {
+	mtx         sync.Mutex
+	content     string
+	etag        string
+	contentType string
+	requests    int32
+	notModified int32
+}
+
+func (c *configServer) set(content string, etag string) {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	c.content = content
+	c.etag = etag
+}
+
+func (c *configServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	atomic.AddInt32(&c.requests, 1)
+
+	c.mtx.Lock()
+	content, etag, contentType := c.content, c.etag, c.contentType
+	c.mtx.Unlock()
+
+	if r.Header.Get("If-None-Match") == etag {
+		atomic.AddInt32(&c.notModified, 1)
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Content-Type", contentType)
+	_, _ = w.Write([]byte(content))
+}
+
+func TestHttpConfigReaderReadConfig(t *testing.T) {
+	server := &configServer{contentType: "application/yaml"}
+	server.set("field1:\n  field11: 123\nfield2: \"{{param1}}\"\n", `"v1"`)
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	cachePath := filepath.Join(t.TempDir(), "cache", "config.yml")
+	reader := config.NewEmptyHttpConfigReader()
+	reader.Configure(context.Background(), cconfig.NewConfigParamsFromTuples(
+		"url", httpServer.URL+"/config",
+		"options.cache_path", cachePath,
+	))
+
+	parameters := cconfig.NewConfigParamsFromTuples("param1", "ABC")
+	conf, err := reader.ReadConfig(context.Background(), "", parameters)
+	assert.Nil(t, err)
+	assert.Equal(t, 123, conf.GetAsInteger("field1.field11"))
+	assert.Equal(t, "ABC", conf.GetAsString("field2"))
+
+	// The second read is served from memory after 304 response
+	conf, err = reader.ReadConfig(context.Background(), "", parameters)
+	assert.Nil(t, err)
+	assert.Equal(t, 123, conf.GetAsInteger("field1.field11"))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&server.notModified))
+
+	// When the server is down a new reader uses the disk cache
+	httpServer.Close()
+	reader = config.NewHttpConfigReader(httpServer.URL + "/config")
+	reader.SetCachePath(cachePath)
+	conf, err = reader.ReadConfig(context.Background(), "", parameters)
+	assert.Nil(t, err)
+	assert.Equal(t, 123, conf.GetAsInteger("field1.field11"))
+
+	// Without cache the error is returned
+	reader = config.NewHttpConfigReader(httpServer.URL + "/config")
+	_, err = reader.ReadConfig(context.Background(), "", parameters)
+	assert.NotNil(t, err)
+}
+
+func TestHttpConfigReaderNotifiesOnChange(t *testing.T) {
+	server := &configServer{contentType: "application/json"}
+	server.set(`{ "key1": "A" }`, `"v1"`)
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	reader := config.NewHttpConfigReader(httpServer.URL)
+	reader.SetCheckInterval(10)
+	conf, err := reader.ReadConfig(context.Background(), "", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "A", conf.GetAsString("key1"))
+
+	listener := newChangeListener()
+	reader.AddChangeListener(context.Background(), listener)
+	defer reader.RemoveChangeListener(context.Background(), listener)
+
+	// Unchanged content doesn't notify listeners
+	_, ok := listener.wait(100 * time.Millisecond)
+	assert.False(t, ok)
+	assert.True(t, atomic.LoadInt32(&server.notModified) > 0)
+
+	server.set(`{ "key1": "B" }`, `"v2"`)
+	args, ok := listener.wait(5 * time.Second)
+	assert.True(t, ok)
+	assert.Equal(t, httpServer.URL, args.GetAsString("url"))
+
+	conf, err = reader.ReadConfig(context.Background(), "", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "B", conf.GetAsString("key1"))
+}
+
+func TestHttpConfigReaderKeepsLastGoodConfig(t *testing.T) {
+	server := &configServer{contentType: "application/json"}
+	server.set(`{ "key1": "A" }`, `"v1"`)
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	cachePath := filepath.Join(t.TempDir(), "config.json")
+	reader := config.NewHttpConfigReader(httpServer.URL)
+	reader.SetCachePath(cachePath)
+	conf, err := reader.ReadConfig(context.Background(), "", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "A", conf.GetAsString("key1"))
+
+	// Malformed response doesn't replace the last good config in memory and on disk
+	server.set(`{ "key1": `, `"v2"`)
+	conf, err = reader.ReadConfig(context.Background(), "", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "A", conf.GetAsString("key1"))
+
+	httpServer.Close()
+	reader = config.NewHttpConfigReader(httpServer.URL)
+	reader.SetCachePath(cachePath)
+	conf, err = reader.ReadConfig(context.Background(), "", nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "A", conf.GetAsString("key1"))
+}
+
+func TestHttpConfigReaderInvalidJson(t *testing.T) {
+	server := &configServer{contentType: "application/json"}
+	server.set(`{ "key1": `, `"v1"`)
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	reader := config.NewHttpConfigReader(httpServer.URL)
+	_, err := reader.ReadConfig(context.Background(), "", nil)
+	assert.NotNil(t, err)
+}
+
+func TestHttpConfigReaderUnquotedParameters(t *testing.T) {
+	server := &configServer{contentType: "application/json"}
+	server.set(`{ "host": "A", "port": {{PORT}} }`, `"v1"`)
+	httpServer := httptest.NewServer(server)
+	defer httpServer.Close()
+
+	cachePath := filepath.Join(t.TempDir(), "config.json")
+	reader := config.NewHttpConfigReader(httpServer.URL)
+	reader.SetCachePath(cachePath)
+	reader.SetCheckInterval(10)
+	parameters := cconfig.NewConfigParamsFromTuples("PORT", 8080)
+	conf, err := reader.ReadConfig(context.Background(), "", parameters)
+	assert.Nil(t, err)
+	assert.Equal(t, "A", conf.GetAsString("host"))
+	assert.Equal(t, 8080, conf.GetAsInteger("port"))
+
+	listener := newChangeListener()
+	reader.AddChangeListener(context.Background(), listener)
+	defer reader.RemoveChangeListener(context.Background(), listener)
+
+	server.set(`{ "host": "B", "port": {{PORT}} }`, `"v2"`)
+	_, ok := listener.wait(5 * time.Second)
+	assert.True(t, ok)
+
+	conf, err = reader.ReadConfig(context.Background(), "", parameters)
+	assert.Nil(t, err)
+	assert.Equal(t, "B", conf.GetAsString("host"))
+	assert.Equal(t, 8080, conf.GetAsInteger("port"))
+
+	httpServer.Close()
+	reader = config.NewHttpConfigReader(httpServer.URL)
+	reader.SetCachePath(cachePath)
+	conf, err = reader.ReadConfig(context.Background(), "", parameters)
+	assert.Nil(t, err)
+	assert.Equal(t, "B", conf.GetAsString("host"))
+}
@@ -2,6 +2,8 @@ package test_config
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"testing"
 
 	cconfig "github.com/pip-services3-gox/pip-services3-commons-gox/config"
@@ -28,3 +30,38 @@ func TestJsonConfigReader(t *testing.T) {
 	assert.Equal(t, "Test Param 1", config.GetAsString("field4"))
 	assert.Equal(t, "Test Param 2", config.GetAsString("field5"))
 }
+
+func TestJsonConfigReaderIncludes(t *testing.T) {
+	dir := t.TempDir()
+	assert.Nil(t, os.MkdirAll(filepath.Join(dir, "sub"), 0755))
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "config.json"),
+		[]byte(`{ "key1": "{{param1}}", "connection": { "$include": "sub/connection.json" } }`), 0644))
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "sub", "connection.json"),
+		[]byte(`{ "host": "{{param2}}", "options": {"$include": "options.json"} }`), 0644))
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "sub", "options.json"),
+		[]byte(`{ "timeout": 1000 }`), 0644))
+
+	parameters := cconfig.NewConfigParamsFromTuples(
+		"param1", "Test Param 1",
+		"param2", "localhost",
+	)
+	config, err := config.ReadJsonConfig(context.Background(), "", filepath.Join(dir, "config.json"), parameters)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "Test Param 1", config.GetAsString("key1"))
+	assert.Equal(t, "localhost", config.GetAsString("connection.host"))
+	assert.Equal(t, 1000, config.GetAsInteger("connection.options.timeout"))
+}
+
+func TestJsonConfigReaderIncludeCycle(t *testing.T) {
+	dir := t.TempDir()
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "a.json"),
+		[]byte(`{ "b": { "$include": "b.json" } }`), 0644))
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "b.json"),
+		[]byte(`{ "a": { "$include": "a.json" } }`), 0644))
+
+	_, err := config.ReadJsonConfig(context.Background(), "", filepath.Join(dir, "a.json"), nil)
+
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "Cyclic include")
+}
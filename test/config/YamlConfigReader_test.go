@@ -2,6 +2,8 @@ package test_config
 
 import (
 	"context"
+	"os"
+	"path/filepath"
 	"testing"
 
 	cconfig "github.com/pip-services3-gox/pip-services3-commons-gox/config"
@@ -35,3 +37,48 @@ func TestYamlConfigReaderReadArray(t *testing.T) {
 	assert.Nil(t, err)
 	assert.True(t, config.Len() > 0)
 }
+
+func TestYamlConfigReaderIncludes(t *testing.T) {
+	dir := t.TempDir()
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "config.yml"), []byte(`---
+key1: "{{param1}}"
+connection: !include connection.yml
+items:
+  - !include item.yml
+  - name: second
+!include common.yml
+`), 0644))
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "connection.yml"), []byte(`---
+host: "{{param2}}"
+options:
+  timeout: 1000
+`), 0644))
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "item.yml"), []byte("name: first\nvalue: 1\n"), 0644))
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "common.yml"), []byte("common: true\n"), 0644))
+
+	parameters := cconfig.NewConfigParamsFromTuples(
+		"param1", "Test Param 1",
+		"param2", "localhost",
+	)
+	config, err := config.ReadYamlConfig(context.Background(), "", filepath.Join(dir, "config.yml"), parameters)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "Test Param 1", config.GetAsString("key1"))
+	assert.Equal(t, "localhost", config.GetAsString("connection.host"))
+	assert.Equal(t, 1000, config.GetAsInteger("connection.options.timeout"))
+	assert.Equal(t, "first", config.GetAsString("items.0.name"))
+	assert.Equal(t, 1, config.GetAsInteger("items.0.value"))
+	assert.Equal(t, "second", config.GetAsString("items.1.name"))
+	assert.True(t, config.GetAsBoolean("common"))
+}
+
+func TestYamlConfigReaderIncludeCycle(t *testing.T) {
+	dir := t.TempDir()
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "a.yml"), []byte("b: !include b.yml\n"), 0644))
+	assert.Nil(t, os.WriteFile(filepath.Join(dir, "b.yml"), []byte("a: !include a.yml\n"), 0644))
+
+	_, err := config.ReadYamlConfig(context.Background(), "", filepath.Join(dir, "a.yml"), nil)
+
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "Cyclic include")
+}
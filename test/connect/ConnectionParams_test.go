@@ -49,3 +49,35 @@ func TestGetAndSetUri(t *testing.T) {
 	connection.SetUri("https://pipgoals:3000")
 	assert.Equal(t, "https://pipgoals:3000", connection.Uri())
 }
+
+func TestConnectionParamsSsl(t *testing.T) {
+	connection := connect.NewConnectionParamsFromTuples("tls", true)
+	assert.True(t, connection.Ssl())
+
+	connection.SetSsl(false)
+	assert.False(t, connection.Ssl())
+
+	connection.SetSslCaFile("ca.pem")
+	connection.SetSslServerName("example.com")
+	connection.SetSslInsecure(true)
+	assert.Equal(t, "ca.pem", connection.SslCaFile())
+	assert.Equal(t, "example.com", connection.SslServerName())
+	assert.True(t, connection.SslInsecure())
+}
+
+func TestConnectionParamsSslFromFiles(t *testing.T) {
+	connection := connect.NewConnectionParamsFromTuples("ssl_ca_file", "ca.pem")
+	assert.True(t, connection.Ssl())
+
+	connection = connect.NewConnectionParamsFromTuples("ssl_cert_file", "client.pem", "ssl_key_file", "client-key.pem")
+	assert.True(t, connection.Ssl())
+
+	connection.SetSsl(false)
+	assert.False(t, connection.Ssl())
+
+	connection = connect.NewConnectionParamsFromTuples("tls", false, "ssl_ca_file", "ca.pem")
+	assert.False(t, connection.Ssl())
+
+	connection = connect.NewConnectionParamsFromTuples("host", "localhost")
+	assert.False(t, connection.Ssl())
+}
@@ -0,0 +1,96 @@
+package test_connect
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pip-services3-gox/pip-services3-commons-gox/config"
+	cerr "github.com/pip-services3-gox/pip-services3-commons-gox/errors"
+	"github.com/pip-services3-gox/pip-services3-components-gox/connect"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConnectionProfilesGet(t *testing.T) {
+	assert.Subset(t, connect.ConnectionProfiles.Protocols(),
+		[]string{"amqp", "grpc", "http", "https", "kafka", "mongodb", "nats", "postgres", "redis"})
+
+	profile := connect.ConnectionProfiles.Get("PostgreSQL")
+	assert.NotNil(t, profile)
+	assert.Equal(t, "postgres", profile.Protocol)
+	assert.Equal(t, 5432, profile.DefaultPort)
+
+	assert.Nil(t, connect.ConnectionProfiles.Get("unknown"))
+
+	connect.ConnectionProfiles.Register(connect.NewConnectionProfile("mqtt", 1883, nil, "tcp"))
+	profile = connect.ConnectionProfiles.Get("tcp")
+	assert.NotNil(t, profile)
+	assert.Equal(t, "mqtt", profile.Protocol)
+	assert.True(t, profile.IsOptionAllowed("anything"))
+}
+
+func TestConnectionProfileValidate(t *testing.T) {
+	profile := connect.ConnectionProfiles.Get("redis")
+
+	err := profile.Validate("123", connect.NewConnectionParamsFromTuples("host", "localhost"))
+	assert.Nil(t, err)
+
+	err = profile.Validate("123", connect.NewConnectionParamsFromTuples("uri", "redis://:secret@localhost:6380?db=1"))
+	assert.Nil(t, err)
+
+	err = profile.Validate("123", connect.NewConnectionParamsFromTuples("protocol", "http", "host", "localhost"))
+	assert.Equal(t, "UNSUPPORTED_PROTOCOL", err.(*cerr.ApplicationError).Code)
+
+	err = profile.Validate("123", connect.NewConnectionParamsFromTuples("port", 6379))
+	assert.Equal(t, "NO_HOST", err.(*cerr.ApplicationError).Code)
+
+	err = profile.Validate("123", connect.NewConnectionParamsFromTuples("uri", "redis://localhost:70000"))
+	assert.Equal(t, "INVALID_PORT", err.(*cerr.ApplicationError).Code)
+
+	err = profile.Validate("123", connect.NewConnectionParamsFromTuples("uri", "redis://localhost?database=1"))
+	assert.Equal(t, "UNSUPPORTED_OPTION", err.(*cerr.ApplicationError).Code)
+}
+
+func TestConnectionProfileNormalize(t *testing.T) {
+	profile := connect.ConnectionProfiles.Get("postgres")
+
+	connection := connect.NewConnectionParamsFromTuples("uri", "POSTGRESQL://localhost/mydb?sslmode=require")
+	normalized := profile.Normalize(connection)
+	assert.Equal(t, "postgres://localhost:5432/mydb?sslmode=require", normalized.Uri())
+	assert.Equal(t, "postgres", normalized.Protocol())
+	assert.Equal(t, "localhost", normalized.Host())
+	assert.Equal(t, 5432, normalized.Port())
+	assert.Equal(t, "POSTGRESQL://localhost/mydb?sslmode=require", connection.Uri())
+
+	normalized = profile.Normalize(connect.NewConnectionParamsFromTuples("protocol", "postgresql", "host", "db1"))
+	assert.Equal(t, "postgres", normalized.Protocol())
+	assert.Equal(t, 5432, normalized.Port())
+}
+
+func TestCompositeConnectionResolverWithProfiles(t *testing.T) {
+	resolver := connect.InheritCompositeConnectionResolver(nil)
+	resolver.Overrides = resolver
+
+	err := resolver.UseProfiles("unknown")
+	assert.NotNil(t, err)
+
+	err = resolver.UseProfiles("mongodb")
+	assert.Nil(t, err)
+	assert.Equal(t, "mongodb", resolver.DefaultProtocol)
+	assert.Equal(t, 27017, resolver.DefaultPort)
+
+	resolver.Configure(context.Background(), config.NewConfigParamsFromTuples(
+		"connection.host", "localhost",
+		"connection.replicaSet", "rs0",
+	))
+	options, err := resolver.Resolve("123")
+	assert.Nil(t, err)
+	assert.Equal(t, "mongodb", options.GetAsString("protocol"))
+	assert.Equal(t, "27017", options.GetAsString("port"))
+	assert.Equal(t, "rs0", options.GetAsString("replicaSet"))
+
+	resolver.Configure(context.Background(), config.NewConfigParamsFromTuples(
+		"connection.uri", "mongodb://localhost/test?maxPoolSize=10&poolSize=5",
+	))
+	_, err = resolver.Resolve("123")
+	assert.Equal(t, "UNSUPPORTED_OPTION", err.(*cerr.ApplicationError).Code)
+}
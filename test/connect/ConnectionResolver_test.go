@@ -3,9 +3,12 @@ package test_connect
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/pip-services3-gox/pip-services3-commons-gox/config"
+	cerr "github.com/pip-services3-gox/pip-services3-commons-gox/errors"
 	"github.com/pip-services3-gox/pip-services3-commons-gox/refer"
+	"github.com/pip-services3-gox/pip-services3-components-gox/auth"
 	"github.com/pip-services3-gox/pip-services3-components-gox/connect"
 	"github.com/stretchr/testify/assert"
 )
@@ -75,3 +78,314 @@ func TestConnectionResolverResolve(t *testing.T) {
 	assert.NotNil(t, err)
 	assert.Nil(t, connection)
 }
+
+func newMultiConnectionResolver(strategy string) *connect.ConnectionResolver {
+	restConfig := config.NewConfigParamsFromTuples(
+		"connections.0.host", "host1",
+		"connections.0.port", 3000,
+		"connections.0.weight", 1,
+		"connections.0.priority", 2,
+		"connections.1.host", "host2",
+		"connections.1.port", 3000,
+		"connections.1.weight", 3,
+		"connections.1.priority", 1,
+		"connections.2.host", "host3",
+		"connections.2.port", 3000,
+		"connections.2.weight", 0,
+		"connections.2.priority", 3,
+		"options.connection_strategy", strategy,
+	)
+	return connect.NewConnectionResolver(context.Background(), restConfig, nil)
+}
+
+func TestConnectionResolverRoundRobin(t *testing.T) {
+	connectionResolver := newMultiConnectionResolver(connect.ConnectionStrategyRoundRobin)
+
+	hosts := make([]string, 0)
+	for i := 0; i < 4; i++ {
+		connection, err := connectionResolver.Resolve("")
+		assert.Nil(t, err)
+		hosts = append(hosts, connection.Host())
+	}
+	assert.Equal(t, []string{"host1", "host2", "host3", "host1"}, hosts)
+}
+
+func TestConnectionResolverZeroValue(t *testing.T) {
+	var connectionResolver connect.ConnectionResolver
+	connectionResolver.Configure(context.Background(), config.NewConfigParamsFromTuples(
+		"connection.host", "localhost",
+		"options.connection_strategy", connect.ConnectionStrategyRoundRobin,
+	))
+	assert.Equal(t, connect.ConnectionStrategyRoundRobin, connectionResolver.Strategy())
+
+	connection, err := connectionResolver.Resolve("")
+	assert.Nil(t, err)
+	assert.Equal(t, "localhost", connection.Host())
+
+	connectionResolver.MarkUnhealthy(connection)
+	assert.False(t, connectionResolver.IsHealthy(connection))
+}
+
+func TestConnectionResolverUnknownStrategy(t *testing.T) {
+	connectionResolver := newMultiConnectionResolver("fastest")
+
+	_, err := connectionResolver.Resolve("")
+	assert.NotNil(t, err)
+	appErr, ok := err.(*cerr.ApplicationError)
+	assert.True(t, ok)
+	assert.Equal(t, "INVALID_CONNECTION_STRATEGY", appErr.Code)
+}
+
+func TestConnectionResolverRandomAndWeighted(t *testing.T) {
+	connectionResolver := newMultiConnectionResolver(connect.ConnectionStrategyRandom)
+	for i := 0; i < 10; i++ {
+		connection, err := connectionResolver.Resolve("")
+		assert.Nil(t, err)
+		assert.NotNil(t, connection)
+	}
+
+	connectionResolver = newMultiConnectionResolver(connect.ConnectionStrategyWeighted)
+	counts := make(map[string]int)
+	for i := 0; i < 400; i++ {
+		connection, err := connectionResolver.Resolve("")
+		assert.Nil(t, err)
+		counts[connection.Host()]++
+	}
+	assert.Equal(t, 0, counts["host3"])
+	assert.True(t, counts["host2"] > counts["host1"])
+}
+
+func TestConnectionResolverWeightedZeroWeights(t *testing.T) {
+	connectionResolver := newMultiConnectionResolver(connect.ConnectionStrategyWeighted)
+	connections := connectionResolver.GetAll()
+	connectionResolver.MarkUnhealthy(connections[0])
+	connectionResolver.MarkUnhealthy(connections[1])
+
+	for i := 0; i < 10; i++ {
+		connection, err := connectionResolver.Resolve("")
+		assert.Nil(t, err)
+		assert.Equal(t, "host3", connection.Host())
+	}
+
+	restConfig := config.NewConfigParamsFromTuples(
+		"connections.0.host", "host1",
+		"connections.0.weight", 0,
+		"connections.1.host", "host2",
+		"connections.1.weight", 0,
+		"options.connection_strategy", connect.ConnectionStrategyWeighted,
+	)
+	connectionResolver = connect.NewConnectionResolver(context.Background(), restConfig, nil)
+	counts := make(map[string]int)
+	for i := 0; i < 100; i++ {
+		connection, err := connectionResolver.Resolve("")
+		assert.Nil(t, err)
+		counts[connection.Host()]++
+	}
+	assert.True(t, counts["host1"] > 0)
+	assert.True(t, counts["host2"] > 0)
+}
+
+func TestConnectionResolverPriorityFailover(t *testing.T) {
+	connectionResolver := newMultiConnectionResolver(connect.ConnectionStrategyPriority)
+
+	connection, err := connectionResolver.Resolve("")
+	assert.Nil(t, err)
+	assert.Equal(t, "host2", connection.Host())
+
+	connectionResolver.MarkUnhealthy(connection)
+	assert.False(t, connectionResolver.IsHealthy(connection))
+
+	connection, err = connectionResolver.Resolve("")
+	assert.Nil(t, err)
+	assert.Equal(t, "host1", connection.Host())
+
+	connectionResolver.MarkHealthy(connectionResolver.GetAll()[1])
+	connection, err = connectionResolver.Resolve("")
+	assert.Nil(t, err)
+	assert.Equal(t, "host2", connection.Host())
+}
+
+func TestConnectionResolverUnhealthyCooldown(t *testing.T) {
+	connectionResolver := newMultiConnectionResolver(connect.ConnectionStrategyFirst)
+	connections := connectionResolver.GetAll()
+
+	connectionResolver.MarkUnhealthyFor(connections[0], 50*time.Millisecond)
+	connection, err := connectionResolver.Resolve("")
+	assert.Nil(t, err)
+	assert.Equal(t, "host2", connection.Host())
+
+	// When all connections are unhealthy the one recovering first is used
+	connectionResolver.MarkUnhealthyFor(connections[1], time.Minute)
+	connectionResolver.MarkUnhealthyFor(connections[2], time.Minute)
+	connection, err = connectionResolver.Resolve("")
+	assert.Nil(t, err)
+	assert.Equal(t, "host1", connection.Host())
+
+	time.Sleep(60 * time.Millisecond)
+	assert.True(t, connectionResolver.IsHealthy(connections[0]))
+}
+
+type legacyDiscovery struct {
+	items map[string][]*connect.ConnectionParams
+}
+
+func (c *legacyDiscovery) Register(correlationId string, key string,
+	connection *connect.ConnectionParams) (*connect.ConnectionParams, error) {
+
+	c.items[key] = append(c.items[key], connection)
+	return connection, nil
+}
+
+func (c *legacyDiscovery) ResolveOne(correlationId string, key string) (*connect.ConnectionParams, error) {
+	if len(c.items[key]) == 0 {
+		return nil, nil
+	}
+	return c.items[key][0], nil
+}
+
+func (c *legacyDiscovery) ResolveAll(correlationId string, key string) ([]*connect.ConnectionParams, error) {
+	return c.items[key], nil
+}
+
+func TestConnectionResolverUnregister(t *testing.T) {
+	discovery := connect.NewEmptyMemoryDiscovery()
+	references := refer.NewReferencesFromTuples(context.Background(),
+		refer.NewDescriptor("pip-services", "discovery", "memory", "default", "1.0"), discovery,
+	)
+	connectionResolver := connect.NewConnectionResolver(context.Background(), nil, references)
+
+	connection := connect.NewConnectionParamsFromTuples(
+		"discovery_key", "service",
+		"host", "localhost",
+	)
+	err := connectionResolver.Register("", connection)
+	assert.Nil(t, err)
+	assert.Len(t, connectionResolver.GetAll(), 1)
+
+	connections, err := discovery.ResolveAll(context.Background(), "", "service")
+	assert.Nil(t, err)
+	assert.Len(t, connections, 1)
+
+	err = connectionResolver.Unregister("", connection)
+	assert.Nil(t, err)
+	assert.Len(t, connectionResolver.GetAll(), 0)
+
+	connections, err = discovery.ResolveAll(context.Background(), "", "service")
+	assert.Nil(t, err)
+	assert.Len(t, connections, 0)
+}
+
+func TestConnectionResolverDiscoveryAdapter(t *testing.T) {
+	discovery := &legacyDiscovery{items: map[string][]*connect.ConnectionParams{
+		"service": {connect.NewConnectionParamsFromTuples("host", "legacy")},
+	}}
+	references := refer.NewReferencesFromTuples(context.Background(),
+		refer.NewDescriptor("pip-services", "discovery", "legacy", "default", "1.0"), discovery,
+	)
+	restConfig := config.NewConfigParamsFromTuples(
+		"connection.discovery_key", "service",
+	)
+	connectionResolver := connect.NewConnectionResolver(context.Background(), restConfig, references)
+
+	connection, err := connectionResolver.Resolve("")
+	assert.Nil(t, err)
+	assert.Equal(t, "legacy", connection.Host())
+
+	adapter := connect.NewDiscoveryContextAdapter(discovery)
+	err = adapter.Unregister(context.Background(), "", "service", nil)
+	assert.NotNil(t, err)
+}
+
+type testContextKey string
+
+// contextDiscovery implements IContextDiscovery and records contexts passed by resolvers.
+type contextDiscovery struct {
+	discovery *connect.MemoryDiscovery
+	values    []any
+}
+
+func (c *contextDiscovery) Register(ctx context.Context, correlationId string, key string,
+	connection *connect.ConnectionParams) (*connect.ConnectionParams, error) {
+
+	return c.discovery.Register(ctx, correlationId, key, connection)
+}
+
+func (c *contextDiscovery) Unregister(ctx context.Context, correlationId string, key string,
+	connection *connect.ConnectionParams) error {
+
+	return c.discovery.Unregister(ctx, correlationId, key, connection)
+}
+
+func (c *contextDiscovery) ResolveOne(ctx context.Context, correlationId string, key string) (*connect.ConnectionParams, error) {
+	return c.discovery.ResolveOne(ctx, correlationId, key)
+}
+
+func (c *contextDiscovery) ResolveAll(ctx context.Context, correlationId string, key string) ([]*connect.ConnectionParams, error) {
+	c.values = append(c.values, ctx.Value(testContextKey("request")))
+	return c.discovery.ResolveAll(ctx, correlationId, key)
+}
+
+// contextCredentialStore records contexts passed by resolvers.
+type contextCredentialStore struct {
+	*auth.MemoryCredentialStore
+	values []any
+}
+
+func (c *contextCredentialStore) Lookup(ctx context.Context, correlationId string, key string) (*auth.CredentialParams, error) {
+	c.values = append(c.values, ctx.Value(testContextKey("request")))
+	return c.MemoryCredentialStore.Lookup(ctx, correlationId, key)
+}
+
+func TestConnectionResolverWithContext(t *testing.T) {
+	discovery := &contextDiscovery{discovery: connect.NewEmptyMemoryDiscovery()}
+	references := refer.NewReferencesFromTuples(context.Background(),
+		refer.NewDescriptor("pip-services", "discovery", "memory", "default", "1.0"), discovery,
+	)
+	connectionResolver := connect.NewConnectionResolver(context.Background(), config.NewConfigParamsFromTuples(
+		"connection.discovery_key", "service",
+	), references)
+
+	ctx := context.WithValue(context.Background(), testContextKey("request"), "abc")
+	err := connectionResolver.RegisterWithContext(ctx, "", connect.NewConnectionParamsFromTuples(
+		"discovery_key", "service",
+		"host", "localhost",
+	))
+	assert.Nil(t, err)
+
+	connections, err := connectionResolver.ResolveAllWithContext(ctx, "")
+	assert.Nil(t, err)
+	assert.Len(t, connections, 2)
+	assert.Equal(t, []any{"abc", "abc"}, discovery.values)
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	_, err = connectionResolver.ResolveWithContext(cancelled, "")
+	assert.ErrorIs(t, err, context.Canceled)
+
+	err = connectionResolver.UnregisterWithContext(cancelled, "", connections[1])
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestCompositeConnectionResolverWithContext(t *testing.T) {
+	store := &contextCredentialStore{MemoryCredentialStore: auth.NewEmptyMemoryCredentialStore()}
+	store.Store(context.Background(), "", "db", auth.NewCredentialParamsFromTuples("username", "admin"))
+	references := refer.NewReferencesFromTuples(context.Background(),
+		refer.NewDescriptor("pip-services", "credential_store", "memory", "default", "1.0"), store,
+	)
+
+	resolver := connect.InheritCompositeConnectionResolver(nil)
+	resolver.Overrides = resolver
+	resolver.Configure(context.Background(), config.NewConfigParamsFromTuples(
+		"connection.protocol", "http",
+		"connection.host", "localhost",
+		"connection.port", 8080,
+		"credential.store_key", "db",
+	))
+	resolver.SetReferences(context.Background(), references)
+
+	ctx := context.WithValue(context.Background(), testContextKey("request"), "abc")
+	options, err := resolver.ResolveWithContext(ctx, "")
+	assert.Nil(t, err)
+	assert.Equal(t, "admin", options.GetAsString("username"))
+	assert.Equal(t, []any{"abc"}, store.values)
+}
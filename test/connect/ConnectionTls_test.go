@@ -0,0 +1,149 @@
+package test_connect
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pip-services3-gox/pip-services3-commons-gox/config"
+	cerr "github.com/pip-services3-gox/pip-services3-commons-gox/errors"
+	"github.com/pip-services3-gox/pip-services3-components-gox/auth"
+	"github.com/pip-services3-gox/pip-services3-components-gox/connect"
+	"github.com/stretchr/testify/assert"
+)
+
+// writeTestCertificate writes a self-signed certificate and its key into PEM files.
+func writeTestCertificate(t *testing.T, dir string, name string, notAfter time.Time) (string, string) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	assert.Nil(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: name},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              notAfter,
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign | x509.KeyUsageDigitalSignature,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	assert.Nil(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	assert.Nil(t, err)
+
+	certFile := filepath.Join(dir, name+".pem")
+	keyFile := filepath.Join(dir, name+"-key.pem")
+	assert.Nil(t, os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0600))
+	assert.Nil(t, os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0600))
+	return certFile, keyFile
+}
+
+func TestConnectionUtilsComposeTlsConfig(t *testing.T) {
+	dir := t.TempDir()
+	caFile, _ := writeTestCertificate(t, dir, "ca", time.Now().Add(time.Hour))
+	certFile, keyFile := writeTestCertificate(t, dir, "client", time.Now().Add(time.Hour))
+
+	tlsConfig, err := connect.ConnectionUtils.ComposeTlsConfig("123", connect.NewConnectionParamsFromTuples("host", "localhost"), nil)
+	assert.Nil(t, err)
+	assert.Nil(t, tlsConfig)
+
+	connection := connect.NewConnectionParamsFromTuples(
+		"host", "localhost",
+		"ssl", true,
+		"ssl_ca_file", caFile,
+		"ssl_server_name", "example.com",
+	)
+	credential := auth.NewCredentialParamsFromTuples(
+		"ssl_cert_file", certFile,
+		"ssl_key_file", keyFile,
+	)
+	tlsConfig, err = connect.ConnectionUtils.ComposeTlsConfig("123", connection, credential)
+	assert.Nil(t, err)
+	assert.NotNil(t, tlsConfig.RootCAs)
+	assert.Equal(t, "example.com", tlsConfig.ServerName)
+	assert.Equal(t, uint16(tls.VersionTLS12), tlsConfig.MinVersion)
+	assert.Len(t, tlsConfig.Certificates, 1)
+	assert.Equal(t, "client", tlsConfig.Certificates[0].Leaf.Subject.CommonName)
+
+	connection.SetSslCertFile(certFile)
+	_, err = connect.ConnectionUtils.ComposeTlsConfig("123", connection, nil)
+	assert.Equal(t, "INCOMPLETE_CLIENT_CERT", err.(*cerr.ApplicationError).Code)
+
+	connection.SetSslKeyFile(caFile)
+	_, err = connect.ConnectionUtils.ComposeTlsConfig("123", connection, nil)
+	assert.Equal(t, "INVALID_CLIENT_CERT", err.(*cerr.ApplicationError).Code)
+
+	expiredCert, expiredKey := writeTestCertificate(t, dir, "expired", time.Now().Add(-time.Minute))
+	credential = auth.NewCredentialParamsFromTuples("ssl_cert_file", expiredCert, "ssl_key_file", expiredKey)
+	_, err = connect.ConnectionUtils.ComposeTlsConfig("123", connection, credential)
+	assert.Equal(t, "CLIENT_CERT_EXPIRED", err.(*cerr.ApplicationError).Code)
+
+	connection = connect.NewConnectionParamsFromTuples("ssl", true, "ssl_ca_file", keyFile)
+	_, err = connect.ConnectionUtils.ComposeTlsConfig("123", connection, nil)
+	assert.Equal(t, "INVALID_CA_FILE", err.(*cerr.ApplicationError).Code)
+
+	connection = connect.NewConnectionParamsFromTuples("ssl", true, "ssl_ca_file", filepath.Join(dir, "missing.pem"))
+	_, err = connect.ConnectionUtils.ComposeTlsConfig("123", connection, nil)
+	assert.Equal(t, "READ_FAILED", err.(*cerr.ApplicationError).Code)
+}
+
+func TestConnectionUtilsComposeTlsConfigImplicit(t *testing.T) {
+	dir := t.TempDir()
+	caFile, _ := writeTestCertificate(t, dir, "ca", time.Now().Add(time.Hour))
+	certFile, keyFile := writeTestCertificate(t, dir, "client", time.Now().Add(time.Hour))
+
+	connection := connect.NewConnectionParamsFromTuples("host", "localhost", "ssl_ca_file", caFile)
+	tlsConfig, err := connect.ConnectionUtils.ComposeTlsConfig("123", connection, nil)
+	assert.Nil(t, err)
+	assert.NotNil(t, tlsConfig)
+	assert.NotNil(t, tlsConfig.RootCAs)
+
+	connection = connect.NewConnectionParamsFromTuples("host", "localhost")
+	credential := auth.NewCredentialParamsFromTuples("ssl_cert_file", certFile, "ssl_key_file", keyFile)
+	tlsConfig, err = connect.ConnectionUtils.ComposeTlsConfig("123", connection, credential)
+	assert.Nil(t, err)
+	assert.NotNil(t, tlsConfig)
+	assert.Len(t, tlsConfig.Certificates, 1)
+
+	connection = connect.NewConnectionParamsFromTuples("host", "localhost", "ssl_cert_file", certFile)
+	_, err = connect.ConnectionUtils.ComposeTlsConfig("123", connection, nil)
+	assert.Equal(t, "INCOMPLETE_CLIENT_CERT", err.(*cerr.ApplicationError).Code)
+
+	connection = connect.NewConnectionParamsFromTuples("host", "localhost", "tls", false, "ssl_ca_file", caFile)
+	tlsConfig, err = connect.ConnectionUtils.ComposeTlsConfig("123", connection, nil)
+	assert.Nil(t, err)
+	assert.Nil(t, tlsConfig)
+}
+
+func TestCompositeConnectionResolverComposeTlsConfig(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeTestCertificate(t, dir, "client", time.Now().Add(time.Hour))
+
+	resolver := connect.InheritCompositeConnectionResolver(nil)
+	resolver.Overrides = resolver
+	err := resolver.UseProfiles("redis")
+	assert.Nil(t, err)
+
+	resolver.Configure(context.Background(), config.NewConfigParamsFromTuples(
+		"connection.host", "localhost",
+		"connection.ssl", true,
+		"credential.ssl_cert_file", certFile,
+		"credential.ssl_key_file", keyFile,
+	))
+	options, err := resolver.Resolve("123")
+	assert.Nil(t, err)
+
+	tlsConfig, err := resolver.ComposeTlsConfig("123", options)
+	assert.Nil(t, err)
+	assert.Len(t, tlsConfig.Certificates, 1)
+}
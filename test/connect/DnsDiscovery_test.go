@@ -0,0 +1,129 @@
+package test_connect
+
+import (
+	"context"
+	"errors"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/pip-services3-gox/pip-services3-commons-gox/config"
+	"github.com/pip-services3-gox/pip-services3-components-gox/connect"
+	"github.com/stretchr/testify/assert"
+)
+
+type testDnsResolver struct {
+	srv     map[string][]*net.SRV
+	hosts   map[string][]string
+	lookups int32
+	failing atomic.Bool
+}
+
+func (c *testDnsResolver) LookupSRV(ctx context.Context, service, proto, name string) (string, []*net.SRV, error) {
+	atomic.AddInt32(&c.lookups, 1)
+	if c.failing.Load() {
+		return "", nil, errors.New("dns is not available")
+	}
+	records, ok := c.srv[name]
+	if !ok {
+		return "", nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+	}
+	return name, records, nil
+}
+
+func (c *testDnsResolver) LookupHost(ctx context.Context, host string) ([]string, error) {
+	atomic.AddInt32(&c.lookups, 1)
+	if c.failing.Load() {
+		return nil, errors.New("dns is not available")
+	}
+	addresses, ok := c.hosts[host]
+	if !ok {
+		return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+	}
+	return addresses, nil
+}
+
+func newTestDnsResolver() *testDnsResolver {
+	return &testDnsResolver{
+		srv: map[string][]*net.SRV{
+			"_grpc._tcp.service1.default.svc.cluster.local": {
+				{Target: "pod1.service1.default.svc.cluster.local.", Port: 9000, Priority: 10, Weight: 60},
+				{Target: "pod2.service1.default.svc.cluster.local.", Port: 9001, Priority: 20, Weight: 40},
+			},
+		},
+		hosts: map[string][]string{
+			"service2.default.svc.cluster.local": {"10.1.1.100", "10.1.1.101"},
+		},
+	}
+}
+
+func TestDnsDiscoverySrv(t *testing.T) {
+	resolver := newTestDnsResolver()
+	discovery := connect.NewDnsDiscovery(resolver)
+
+	connections, err := discovery.ResolveAll(context.Background(), "123", "_grpc._tcp.service1.default.svc.cluster.local")
+	assert.Nil(t, err)
+	assert.Len(t, connections, 2)
+	assert.Equal(t, "grpc", connections[0].Protocol())
+	assert.Equal(t, "pod1.service1.default.svc.cluster.local", connections[0].Host())
+	assert.Equal(t, 9000, connections[0].Port())
+	assert.Equal(t, 10, connections[0].Priority())
+	assert.Equal(t, 60, connections[0].Weight())
+
+	_, err = discovery.ResolveAll(context.Background(), "123", "_http._tcp.unknown")
+	assert.NotNil(t, err)
+}
+
+func TestDnsDiscoveryHostAndCache(t *testing.T) {
+	resolver := newTestDnsResolver()
+	discovery := connect.NewEmptyDnsDiscovery()
+	discovery.SetResolver(resolver)
+	discovery.Configure(context.Background(), config.NewConfigParamsFromTuples(
+		"options.protocol", "http",
+		"options.port", 8080,
+		"options.ttl", 50,
+	))
+
+	connection, err := discovery.ResolveOne(context.Background(), "123", "service2.default.svc.cluster.local")
+	assert.Nil(t, err)
+	assert.Equal(t, "http", connection.Protocol())
+	assert.Equal(t, "10.1.1.100", connection.Host())
+	assert.Equal(t, 8080, connection.Port())
+
+	// Cached connections are returned without lookups
+	_, err = discovery.ResolveAll(context.Background(), "123", "service2.default.svc.cluster.local")
+	assert.Nil(t, err)
+	assert.Equal(t, int32(1), atomic.LoadInt32(&resolver.lookups))
+
+	// Stale connections are returned when DNS fails after the cache expires
+	time.Sleep(60 * time.Millisecond)
+	resolver.failing.Store(true)
+	connections, err := discovery.ResolveAll(context.Background(), "123", "service2.default.svc.cluster.local")
+	assert.Nil(t, err)
+	assert.Len(t, connections, 2)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&resolver.lookups))
+
+	discovery.ClearCache()
+	_, err = discovery.ResolveAll(context.Background(), "123", "service2.default.svc.cluster.local")
+	assert.NotNil(t, err)
+}
+
+func TestDnsDiscoveryReturnsCopies(t *testing.T) {
+	discovery := connect.NewDnsDiscovery(newTestDnsResolver())
+	discovery.Configure(context.Background(), config.NewConfigParamsFromTuples(
+		"options.port", 8080,
+	))
+
+	connections, err := discovery.ResolveAll(context.Background(), "123", "service2.default.svc.cluster.local")
+	assert.Nil(t, err)
+	assert.Len(t, connections, 2)
+	connections[0].SetHost("changed")
+	connections[1] = nil
+
+	connections, err = discovery.ResolveAll(context.Background(), "123", "service2.default.svc.cluster.local")
+	assert.Nil(t, err)
+	assert.Len(t, connections, 2)
+	assert.Equal(t, "10.1.1.100", connections[0].Host())
+	assert.NotNil(t, connections[1])
+}
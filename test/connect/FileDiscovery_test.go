@@ -0,0 +1,99 @@
+package test_connect
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pip-services3-gox/pip-services3-commons-gox/config"
+	"github.com/pip-services3-gox/pip-services3-components-gox/connect"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFileDiscoveryYaml(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "discovery.yml")
+	assert.Nil(t, os.WriteFile(path, []byte(`
+service1:
+  - host: 10.1.1.100
+    port: 8080
+  - host: 10.1.1.101
+    port: 8081
+service2:
+  host: 10.1.1.102
+  port: 8082
+service3: "mongodb://10.1.1.103:27017/test"
+`), 0644))
+
+	discovery := connect.NewEmptyFileDiscovery()
+	discovery.Configure(context.Background(), config.NewConfigParamsFromTuples(
+		"path", path,
+	))
+
+	connections, err := discovery.ResolveAll(context.Background(), "123", "service1")
+	assert.Nil(t, err)
+	assert.Len(t, connections, 2)
+	assert.Equal(t, "10.1.1.101", connections[1].Host())
+	assert.Equal(t, 8081, connections[1].Port())
+
+	connection, err := discovery.ResolveOne(context.Background(), "123", "service2")
+	assert.Nil(t, err)
+	assert.Equal(t, "10.1.1.102", connection.Host())
+
+	connection, err = discovery.ResolveOne(context.Background(), "123", "service3")
+	assert.Nil(t, err)
+	assert.Equal(t, "mongodb://10.1.1.103:27017/test", connection.Uri())
+
+	keys, err := discovery.Keys(context.Background(), "123")
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"service1", "service2", "service3"}, keys)
+}
+
+func TestFileDiscoveryJsonReload(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "discovery.json")
+	assert.Nil(t, os.WriteFile(path, []byte(`{ "service1": { "host": "10.1.1.100", "port": 8080 } }`), 0644))
+
+	discovery := connect.NewFileDiscovery(path)
+	discovery.Configure(context.Background(), config.NewConfigParamsFromTuples(
+		"options.check_interval", 0,
+	))
+
+	connection, err := discovery.ResolveOne(context.Background(), "123", "service1")
+	assert.Nil(t, err)
+	assert.Equal(t, "10.1.1.100", connection.Host())
+
+	// Runtime registrations are kept together with the file connections
+	_, err = discovery.Register(context.Background(), "123", "service1",
+		connect.NewConnectionParamsFromTuples("host", "10.1.1.200"))
+	assert.Nil(t, err)
+
+	assert.Nil(t, os.WriteFile(path, []byte(`{ "service1": [ { "host": "10.1.1.101" }, { "host": "10.1.1.102" } ] }`), 0644))
+	modTime := time.Now().Add(time.Second)
+	assert.Nil(t, os.Chtimes(path, modTime, modTime))
+
+	connections, err := discovery.ResolveAll(context.Background(), "123", "service1")
+	assert.Nil(t, err)
+	assert.Len(t, connections, 3)
+	assert.Equal(t, "10.1.1.101", connections[0].Host())
+	assert.Equal(t, "10.1.1.200", connections[2].Host())
+
+	// Invalid content keeps the last good connections
+	assert.Nil(t, os.WriteFile(path, []byte(`{ "service1": `), 0644))
+	modTime = modTime.Add(time.Second)
+	assert.Nil(t, os.Chtimes(path, modTime, modTime))
+
+	err = discovery.Unregister(context.Background(), "123", "service1", nil)
+	assert.Nil(t, err)
+
+	connections, err = discovery.ResolveAll(context.Background(), "123", "service1")
+	assert.Nil(t, err)
+	assert.Len(t, connections, 2)
+}
+
+func TestFileDiscoveryMissingFile(t *testing.T) {
+	discovery := connect.NewFileDiscovery(filepath.Join(t.TempDir(), "missing.yml"))
+
+	_, err := discovery.ResolveAll(context.Background(), "123", "service1")
+	assert.NotNil(t, err)
+}
@@ -2,7 +2,9 @@ package test_connect
 
 import (
 	"context"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/pip-services3-gox/pip-services3-commons-gox/config"
 	"github.com/pip-services3-gox/pip-services3-components-gox/connect"
@@ -21,25 +23,195 @@ func TestMemoryDiscoveryReadConnections(t *testing.T) {
 	discovery.Configure(context.Background(), config)
 
 	// Resolve one
-	connection, err := discovery.ResolveOne("123", "key1")
+	connection, err := discovery.ResolveOne(context.Background(), "123", "key1")
 
 	assert.Equal(t, err, nil)
 	assert.Equal(t, "10.1.1.100", connection.Host())
 	assert.Equal(t, 8080, connection.Port())
 
-	connection, err = discovery.ResolveOne("123", "key2")
+	connection, err = discovery.ResolveOne(context.Background(), "123", "key2")
 
 	assert.Equal(t, err, nil)
 	assert.Equal(t, "10.1.1.101", connection.Host())
 	assert.Equal(t, 8082, connection.Port())
 
 	// Resolve all
-	_, err = discovery.Register("123", "key1", connect.NewConnectionParamsFromTuples(
+	_, err = discovery.Register(context.Background(), "123", "key1", connect.NewConnectionParamsFromTuples(
 		"host", "10.3.3.151",
 	))
 	assert.Equal(t, err, nil)
 
-	connections, err := discovery.ResolveAll("123", "key1")
+	connections, err := discovery.ResolveAll(context.Background(), "123", "key1")
 	assert.Equal(t, err, nil)
 	assert.Equal(t, len(connections) > 1, true)
 }
+
+func TestMemoryDiscoveryUnregister(t *testing.T) {
+	discovery := connect.NewEmptyMemoryDiscovery()
+	connection1 := connect.NewConnectionParamsFromTuples("host", "10.1.1.100")
+	connection2 := connect.NewConnectionParamsFromTuples("host", "10.1.1.101")
+
+	_, err := discovery.Register(context.Background(), "123", "key1", connection1)
+	assert.Nil(t, err)
+	_, err = discovery.Register(context.Background(), "123", "key1", connection2)
+	assert.Nil(t, err)
+
+	err = discovery.Unregister(context.Background(), "123", "key1",
+		connect.NewConnectionParamsFromTuples("host", "10.1.1.100"))
+	assert.Nil(t, err)
+
+	connections, err := discovery.ResolveAll(context.Background(), "123", "key1")
+	assert.Nil(t, err)
+	assert.Len(t, connections, 1)
+	assert.Equal(t, "10.1.1.101", connections[0].Host())
+
+	err = discovery.Unregister(context.Background(), "123", "key1", nil)
+	assert.Nil(t, err)
+
+	connection, err := discovery.ResolveOne(context.Background(), "123", "key1")
+	assert.Nil(t, err)
+	assert.Nil(t, connection)
+}
+
+func TestMemoryDiscoveryTtlAndHeartbeat(t *testing.T) {
+	discovery := connect.NewEmptyMemoryDiscovery()
+	connection := connect.NewConnectionParamsFromTuples("host", "10.1.1.100")
+
+	_, err := discovery.RegisterWithTtl(context.Background(), "123", "key1", connection, time.Second)
+	assert.Nil(t, err)
+
+	// Registering the same connection again doesn't duplicate it
+	_, err = discovery.RegisterWithTtl(context.Background(), "123", "key1", connection, time.Second)
+	assert.Nil(t, err)
+
+	connections, err := discovery.ResolveAll(context.Background(), "123", "key1")
+	assert.Nil(t, err)
+	assert.Len(t, connections, 1)
+
+	// Wide margins keep the test stable on loaded machines
+	time.Sleep(600 * time.Millisecond)
+	err = discovery.Heartbeat(context.Background(), "123", "key1", connection)
+	assert.Nil(t, err)
+
+	time.Sleep(600 * time.Millisecond)
+	connections, err = discovery.ResolveAll(context.Background(), "123", "key1")
+	assert.Nil(t, err)
+	assert.Len(t, connections, 1)
+
+	time.Sleep(900 * time.Millisecond)
+	connections, err = discovery.ResolveAll(context.Background(), "123", "key1")
+	assert.Nil(t, err)
+	assert.Len(t, connections, 0)
+
+	err = discovery.Heartbeat(context.Background(), "123", "key1", connection)
+	assert.NotNil(t, err)
+}
+
+func TestMemoryDiscoveryHealth(t *testing.T) {
+	discovery := connect.NewEmptyMemoryDiscovery()
+	connection1 := connect.NewConnectionParamsFromTuples("host", "10.1.1.100")
+	connection2 := connect.NewConnectionParamsFromTuples("host", "10.1.1.101")
+
+	_, _ = discovery.Register(context.Background(), "123", "key1", connection1)
+	_, _ = discovery.Register(context.Background(), "123", "key1", connection2)
+
+	err := discovery.SetHealth(context.Background(), "123", "key1", connection1, connect.DiscoveryHealthCritical)
+	assert.Nil(t, err)
+
+	connection, err := discovery.ResolveOne(context.Background(), "123", "key1")
+	assert.Nil(t, err)
+	assert.Equal(t, "10.1.1.101", connection.Host())
+
+	instances, err := discovery.Instances(context.Background(), "123", "key1")
+	assert.Nil(t, err)
+	assert.Len(t, instances, 2)
+	assert.Equal(t, connect.DiscoveryHealthCritical, instances[0].Health)
+	assert.Equal(t, connect.DiscoveryHealthPassing, instances[1].Health)
+
+	err = discovery.SetHealth(context.Background(), "123", "key2", connection1, connect.DiscoveryHealthPassing)
+	assert.NotNil(t, err)
+}
+
+func TestMemoryDiscoveryConcurrentAccess(t *testing.T) {
+	discovery := connect.NewEmptyMemoryDiscovery()
+	discovery.SetTtl(time.Second)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			connection := connect.NewConnectionParamsFromTuples("host", "host", "port", i)
+			for j := 0; j < 100; j++ {
+				_, _ = discovery.Register(context.Background(), "123", "key1", connection)
+				_, _ = discovery.ResolveAll(context.Background(), "123", "key1")
+				_ = discovery.Heartbeat(context.Background(), "123", "key1", connection)
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	connections, err := discovery.ResolveAll(context.Background(), "123", "key1")
+	assert.Nil(t, err)
+	assert.Len(t, connections, 10)
+}
+
+func waitDiscoveryEvent(t *testing.T, events <-chan *connect.DiscoveryEvent) *connect.DiscoveryEvent {
+	select {
+	case event, ok := <-events:
+		assert.True(t, ok)
+		return event
+	case <-time.After(5 * time.Second):
+		assert.Fail(t, "Discovery event was not received")
+		return nil
+	}
+}
+
+func TestMemoryDiscoveryWatch(t *testing.T) {
+	discovery := connect.NewEmptyMemoryDiscovery()
+	connection1 := connect.NewConnectionParamsFromTuples("host", "10.1.1.100")
+	connection2 := connect.NewConnectionParamsFromTuples("host", "10.1.1.101")
+	_, _ = discovery.Register(context.Background(), "123", "key1", connection1)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	events, err := discovery.Watch(ctx, "123", "key1")
+	assert.Nil(t, err)
+
+	event := waitDiscoveryEvent(t, events)
+	assert.Equal(t, "key1", event.Key)
+	assert.Len(t, event.Connections, 1)
+	assert.Len(t, event.Added, 1)
+
+	_, _ = discovery.RegisterWithTtl(context.Background(), "123", "key1", connection2, 500*time.Millisecond)
+	event = waitDiscoveryEvent(t, events)
+	assert.Len(t, event.Connections, 2)
+	assert.Len(t, event.Added, 1)
+	assert.Equal(t, "10.1.1.101", event.Added[0].Host())
+
+	// Expired registration is reported without other calls
+	event = waitDiscoveryEvent(t, events)
+	assert.Len(t, event.Connections, 1)
+	assert.Len(t, event.Removed, 1)
+	assert.Equal(t, "10.1.1.101", event.Removed[0].Host())
+
+	_ = discovery.SetHealth(context.Background(), "123", "key1", connection1, connect.DiscoveryHealthCritical)
+	event = waitDiscoveryEvent(t, events)
+	assert.Len(t, event.Connections, 0)
+	assert.Len(t, event.Removed, 1)
+
+	// Changes of other keys are not reported
+	_, _ = discovery.Register(context.Background(), "123", "key2", connection2)
+	select {
+	case event = <-events:
+		assert.Fail(t, "Unexpected discovery event")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	cancel()
+	select {
+	case _, ok := <-events:
+		assert.False(t, ok)
+	case <-time.After(5 * time.Second):
+		assert.Fail(t, "Watch channel was not closed")
+	}
+}
@@ -0,0 +1,56 @@
+package test_state
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pip-services3-gox/pip-services3-components-gox/state"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMemoryStateStoreSaveAndDeleteMany(t *testing.T) {
+	store := state.NewEmptyMemoryStateStore[string]()
+
+	saved, err := store.SaveMany(context.Background(), "123", []state.StateValue[string]{
+		{Key: "order", Value: "created"},
+		{Key: "payment", Value: "pending"},
+	})
+	assert.Nil(t, err)
+	assert.Len(t, saved, 2)
+
+	values := store.LoadBulk(context.Background(), "123", []string{"order", "payment"})
+	assert.Len(t, values, 2)
+	assert.Equal(t, "created", values[0].Value)
+	assert.Equal(t, "pending", values[1].Value)
+
+	deleted, err := store.DeleteMany(context.Background(), "123", []string{"order", "payment", "unknown"})
+	assert.Nil(t, err)
+	assert.Len(t, deleted, 2)
+
+	values = store.LoadBulk(context.Background(), "123", []string{"order", "payment"})
+	assert.Len(t, values, 0)
+}
+
+func TestMemoryStateStoreSaveManyIsAtomic(t *testing.T) {
+	store := state.NewEmptyMemoryStateStore[string]()
+	_, err := store.SaveMany(context.Background(), "123", []state.StateValue[string]{
+		{Key: "order", Value: "created"},
+	})
+	assert.Nil(t, err)
+
+	_, err = store.SaveMany(context.Background(), "123", []state.StateValue[string]{
+		{Key: "order", Value: "paid"},
+		{Key: "", Value: "completed"},
+	})
+	assert.NotNil(t, err)
+
+	values := store.LoadBulk(context.Background(), "123", []string{"order"})
+	assert.Len(t, values, 1)
+	assert.Equal(t, "created", values[0].Value)
+
+	_, err = store.DeleteMany(context.Background(), "123", []string{"order", ""})
+	assert.NotNil(t, err)
+
+	values = store.LoadBulk(context.Background(), "123", []string{"order"})
+	assert.Len(t, values, 1)
+}